@@ -0,0 +1,564 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goamaan/valocli/internal/chat"
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/events"
+	"github.com/goamaan/valocli/internal/metrics"
+	"github.com/goamaan/valocli/internal/player"
+	"github.com/goamaan/valocli/internal/server"
+	"github.com/goamaan/valocli/internal/store"
+)
+
+const (
+	// give riot a moment to rotate the store before asking for the new one
+	storeResetGrace = 30 * time.Second
+
+	clearScreen = "\033[H\033[2J"
+)
+
+type command struct {
+	Name        string
+	Description string
+	Run         func(c *core.Client, config AuthConfiguration, args []string) error
+}
+
+var commands = []command{
+	{Name: "store", Description: "Check store (--wait to wait for the next daily reset)", Run: runStore},
+	{Name: "wallet", Description: "Check wallet", Run: runWallet},
+	{Name: "mmr", Description: "Check MMR (Rank Data), --player \"name#tag,...\" to look up other players", Run: runMMR},
+	{Name: "pregame", Description: "Show agent select: map, mode and your team's agents, levels and ranks", Run: runPregame},
+	{Name: "live", Description: "Show all players in your current match with ranks, peak ranks and win rates", Run: runLive},
+	{Name: "loadout", Description: "Show your equipped skins, buddies, sprays, card and title", Run: runLoadout},
+	{Name: "preset", Description: "Loadout presets: preset save|apply <name>, preset diff <name> [name], preset list", Run: runPreset},
+	{Name: "contracts", Description: "Show battlepass and agent contract progress with time-to-finish estimates", Run: runContracts},
+	{Name: "missions", Description: "Show daily and weekly mission progress, xp rewards and expiry", Run: runMissions},
+	{Name: "xp", Description: "Show account level and xp history per match and per day", Run: runXP},
+	{Name: "party", Description: "Show your party, or manage it: party invite|kick <name#tag>, party queue <queue>, party open|close", Run: runParty},
+	{Name: "status", Description: "Show active penalties, queue restrictions and chat bans, and whether you can queue ranked", Run: runStatus},
+	{Name: "leaderboard", Description: "Show the ranked leaderboard of your region, with your and your friends' positions", Run: runLeaderboard},
+	{Name: "premier", Description: "Show your premier team, division standings, match windows and results (--ical <file> to export the schedule)", Run: runPremier},
+	{Name: "friends", Description: "Show your friends list with what everyone is doing in VALORANT", Run: runFriends},
+	{Name: "chat", Description: "Chat with a friend or your party: chat <name#tag>|--party [message]", Run: runChat},
+	{Name: "serve", Description: "Run a local http server with store, wallet, mmr, matches and loadout as json", Run: runServe},
+	{Name: "watch", Description: "Send store rotation, night market, wishlist and rank change events to webhooks", Run: runWatch},
+	{Name: "exporter", Description: "Serve rank, wallet and progress as prometheus metrics", Run: runExporter},
+	{Name: "afford", Description: "Check which store offers you can afford (--buy <item> for VP pack suggestions)", Run: runAfford},
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].Name == name {
+			return &commands[i]
+		}
+	}
+
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: valocli [--lang <language>] [command] [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Without a command valocli starts in interactive mode.")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.Name, cmd.Description)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Flags:")
+	flag.PrintDefaults()
+}
+
+// withReauth runs fn again after logging back in if riot rejected the tokens
+func withReauth(c *core.Client, config AuthConfiguration, fn func() error) error {
+	err := fn()
+	if err != core.ErrorRiotAuthentication {
+		return err
+	}
+
+	if err = refreshTokens(c, config, true); err != nil {
+		return err
+	}
+
+	return fn()
+}
+
+func runStore(c *core.Client, config AuthConfiguration, args []string) error {
+	fs := flag.NewFlagSet("store", flag.ExitOnError)
+	wait := fs.Bool("wait", false, "sleep until the next daily store reset, then print the new store")
+	fs.Parse(args)
+
+	if !*wait {
+		return withReauth(c, config, func() error { return store.GetStorefront(c) })
+	}
+
+	reset, err := store.NextDailyReset(c)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Daily store resets at %s (in %s), waiting...\n",
+		reset.Format("Mon 02 Jan 15:04"), store.FormatCountdown(time.Until(reset)))
+	time.Sleep(time.Until(reset) + storeResetGrace)
+
+	return withReauth(c, config, func() error { return store.GetStorefront(c) })
+}
+
+func runWallet(c *core.Client, config AuthConfiguration, args []string) error {
+	return store.GetWallet(c)
+}
+
+func runMMR(c *core.Client, config AuthConfiguration, args []string) error {
+	fs := flag.NewFlagSet("mmr", flag.ExitOnError)
+	players := fs.String("player", "", "comma separated riot ids (name#tag) or puuids to look up instead of your own rank")
+	fs.Parse(args)
+
+	if *players == "" {
+		return player.GetPlayerMMR(c)
+	}
+
+	riotIds := []string{}
+	for _, riotId := range strings.Split(*players, ",") {
+		if riotId = strings.TrimSpace(riotId); riotId != "" {
+			riotIds = append(riotIds, riotId)
+		}
+	}
+
+	ranks, err := player.GetPlayerRanks(c, riotIds)
+	if err != nil {
+		return err
+	}
+
+	player.PrintPlayerRanks(ranks)
+	return nil
+}
+
+func runAfford(c *core.Client, config AuthConfiguration, args []string) error {
+	fs := flag.NewFlagSet("afford", flag.ExitOnError)
+	buy := fs.String("buy", "", "name (or part of the name) of the offer you want to buy")
+	currency := fs.String("currency", "", "currency of the VP pack price table (default from config, or USD)")
+	fs.Parse(args)
+
+	packCurrency, packs := config.vpPacks(*currency)
+	return store.GetAffordability(c, *buy, packs, packCurrency)
+}
+
+func runPregame(c *core.Client, config AuthConfiguration, args []string) error {
+	return player.GetPregame(c)
+}
+
+func runLive(c *core.Client, config AuthConfiguration, args []string) error {
+	fs := flag.NewFlagSet("live", flag.ExitOnError)
+	interval := fs.Duration("interval", 30*time.Second, "how often to refresh, 0 to print once")
+	fs.Parse(args)
+
+	for {
+		var live *player.LiveMatch
+		err := withReauth(c, config, func() (err error) {
+			live, err = player.GetLiveMatch(c)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if *interval <= 0 {
+			player.PrintLive(live)
+			return nil
+		}
+
+		fmt.Print(clearScreen)
+		player.PrintLive(live)
+		fmt.Printf("\nRefreshing every %s, press Ctrl+C to stop\n", *interval)
+		time.Sleep(*interval)
+	}
+}
+
+func runLoadout(c *core.Client, config AuthConfiguration, args []string) error {
+	return player.GetLoadout(c)
+}
+
+func runPreset(c *core.Client, config AuthConfiguration, args []string) error {
+	dir := getPresetsDirectory()
+	if len(args) == 0 {
+		return fmt.Errorf("usage: valocli preset save|apply <name>, preset diff <name> [name], preset list")
+	}
+
+	switch {
+	case args[0] == "list":
+		names, err := player.ListPresets(dir)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			fmt.Println("No presets saved yet, save one with: valocli preset save <name>")
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	case args[0] == "save" && len(args) == 2:
+		if err := player.SavePreset(c, dir, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Saved current loadout as %q\n", args[1])
+		return nil
+	case args[0] == "apply" && len(args) == 2:
+		if err := player.ApplyPreset(c, dir, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Equipped preset %q\n", args[1])
+		return nil
+	case args[0] == "diff" && (len(args) == 2 || len(args) == 3):
+		from, err := player.LoadPreset(dir, args[1])
+		if err != nil {
+			return err
+		}
+
+		// compare against the equipped loadout unless a second preset is given
+		var to *player.PlayerLoadoutResponse
+		if len(args) == 3 {
+			to, err = player.LoadPreset(dir, args[2])
+		} else {
+			to, err = player.FetchPlayerLoadout(c, c.AuthData.UserId)
+		}
+		if err != nil {
+			return err
+		}
+
+		changes, err := player.DiffLoadouts(from, to)
+		if err != nil {
+			return err
+		}
+
+		player.PrintLoadoutChanges(changes)
+		return nil
+	default:
+		return fmt.Errorf("usage: valocli preset save|apply <name>, preset diff <name> [name], preset list")
+	}
+}
+
+func runContracts(c *core.Client, config AuthConfiguration, args []string) error {
+	return player.GetContracts(c)
+}
+
+func runMissions(c *core.Client, config AuthConfiguration, args []string) error {
+	return player.GetMissions(c)
+}
+
+func runXP(c *core.Client, config AuthConfiguration, args []string) error {
+	return player.GetAccountXP(c)
+}
+
+func runParty(c *core.Client, config AuthConfiguration, args []string) error {
+	if len(args) == 0 {
+		return player.GetParty(c)
+	}
+
+	party, err := player.CurrentParty(c)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case args[0] == "invite" && len(args) == 2:
+		err = party.Invite(c, args[1])
+	case args[0] == "kick" && len(args) == 2:
+		err = party.Kick(c, args[1])
+	case args[0] == "queue" && len(args) == 2:
+		warnRestrictions(c, args[1])
+		err = party.SetQueue(c, args[1])
+	case args[0] == "open" && len(args) == 1:
+		err = party.SetOpen(c, true)
+	case args[0] == "close" && len(args) == 1:
+		err = party.SetOpen(c, false)
+	default:
+		return fmt.Errorf("usage: valocli party [invite|kick <name#tag>, queue <queue>, open, close]")
+	}
+	if err != nil {
+		return err
+	}
+
+	return player.GetParty(c)
+}
+
+func runStatus(c *core.Client, config AuthConfiguration, args []string) error {
+	return player.GetStatus(c)
+}
+
+// warnRestrictions tells the player about penalties that will keep them out of a queue
+// before they try it, the game only says so once they hit play
+func warnRestrictions(c *core.Client, queueId string) {
+	restrictions, err := player.GetRestrictions(c)
+	if err != nil {
+		return
+	}
+
+	for _, restriction := range player.QueueBlockers(restrictions, queueId) {
+		fmt.Printf("⚠️ %s: you can't queue %s for %s\n", restriction.Kind, player.QueueName(queueId), restriction.Remaining())
+	}
+}
+
+func runFriends(c *core.Client, config AuthConfiguration, args []string) error {
+	fs := flag.NewFlagSet("friends", flag.ExitOnError)
+	wait := fs.Duration("wait", 3*time.Second, "how long to collect presences before printing")
+	online := fs.Bool("online", false, "only show friends who are online")
+	state := fs.String("state", "", "only show friends in these states, comma separated: menus, pregame, ingame, away")
+	queue := fs.String("queue", "", "only show friends queueing or playing this queue, e.g. competitive")
+	notFull := fs.Bool("not-full", false, "only show friends whose party has room")
+	sortBy := fs.String("sort", "name", "sort by "+strings.Join(chat.SortKeys, ", "))
+	fs.Parse(args)
+
+	filter := chat.FriendFilter{Online: *online, Queue: *queue, NotFull: *notFull}
+	if *state != "" {
+		filter.States = strings.Split(*state, ",")
+	}
+
+	return chat.GetFriends(c, config.chatOptions(), *wait, filter, *sortBy)
+}
+
+func runChat(c *core.Client, config AuthConfiguration, args []string) error {
+	fs := flag.NewFlagSet("chat", flag.ExitOnError)
+	party := fs.Bool("party", false, "chat in your party's chat room")
+	fs.Parse(args)
+
+	if !*party && fs.NArg() == 0 {
+		return fmt.Errorf("usage: valocli chat <name#tag>|--party [message]")
+	}
+
+	conn, err := chat.Connect(c, config.chatOptions())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// the party room or the friend's puuid, used to only show messages from this conversation
+	var send func(body string) error
+	text := fs.Args()
+	conversation := ""
+	if *party {
+		p, err := player.CurrentParty(c)
+		if err != nil {
+			return err
+		}
+
+		conversation = conn.PartyRoom(p.MUCName)
+		if err = conn.JoinRoom(conversation); err != nil {
+			return err
+		}
+		send = func(body string) error { return conn.SendRoom(conversation, body) }
+	} else {
+		friend, err := conn.Friend(fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("%s is not on your friends list", fs.Arg(0))
+		}
+
+		text = text[1:]
+		send = func(body string) error { return conn.Send(friend.PUUID, body) }
+		conversation = friend.PUUID
+	}
+
+	if len(text) > 0 {
+		return send(strings.Join(text, " "))
+	}
+
+	fmt.Println("Type a message and press enter to send it, ctrl+d to quit")
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return nil
+			}
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if err := send(line); err != nil {
+				return err
+			}
+		case message := <-conn.Messages():
+			if message.Room == conversation || (message.Room == "" && message.From == conversation) {
+				chat.PrintMessage(message, conn.Name(c, message.From))
+			}
+		case <-conn.Done():
+			return conn.Err()
+		}
+	}
+}
+
+func runLeaderboard(c *core.Client, config AuthConfiguration, args []string) error {
+	fs := flag.NewFlagSet("leaderboard", flag.ExitOnError)
+	act := fs.String("act", "", "season id of the act (default the current act)")
+	page := fs.Int("page", 1, "page of the leaderboard to show")
+	size := fs.Int("size", player.DefaultLeaderboardSize, "players per page")
+	search := fs.String("search", "", "only show players whose name matches")
+	me := fs.Bool("me", false, "show the page you are on, instead of --page (not with --search)")
+	withFriends := fs.Bool("friends", false, "connect to chat to mark your friends and list their positions")
+	fs.Parse(args)
+
+	if *page < 1 || *size < 1 {
+		return fmt.Errorf("--page and --size must be at least 1")
+	}
+	if *me && *search != "" {
+		return fmt.Errorf("--me can't be combined with --search, search results aren't paged by rank")
+	}
+
+	friends := map[string]string{}
+	if *withFriends {
+		conn, err := chat.Connect(c, config.chatOptions())
+		if err != nil {
+			return err
+		}
+
+		for _, friend := range conn.Friends() {
+			friends[friend.PUUID] = friend.RiotId()
+		}
+		conn.Close()
+	}
+
+	return player.GetLeaderboard(c, *act, *page, *size, *search, *me, friends)
+}
+
+func runPremier(c *core.Client, config AuthConfiguration, args []string) error {
+	fs := flag.NewFlagSet("premier", flag.ExitOnError)
+	ical := fs.String("ical", "", "write the upcoming match windows to this iCalendar (.ics) file")
+	fs.Parse(args)
+
+	if *ical != "" {
+		return player.ExportPremierSchedule(c, *ical)
+	}
+
+	return player.GetPremier(c)
+}
+
+func runServe(c *core.Client, config AuthConfiguration, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", server.DefaultAddr, "address to listen on")
+	cacheTTL := fs.Duration("cache", server.DefaultCacheTTL, "how long responses are cached")
+	fs.Parse(args)
+
+	s := server.New(c, server.Options{
+		Addr:     *addr,
+		CacheTTL: *cacheTTL,
+		Refresh:  func() error { return refreshTokens(c, config, false) },
+	})
+	serveMetrics(c, s, "", metrics.DefaultInterval)
+
+	return s.ListenAndServe()
+}
+
+func runWatch(c *core.Client, config AuthConfiguration, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	interval := fs.Duration("interval", 5*time.Minute, "how often the store and rank are checked")
+	once := fs.Bool("once", false, "check once and exit, e.g. from cron")
+	webhook := fs.String("webhook", "", "post events as json to this url")
+	discord := fs.String("discord", "", "post events to this discord webhook")
+	slack := fs.String("slack", "", "post events to this slack webhook")
+	command := fs.String("exec", "", "run this shell command for every event, with the event as json on stdin")
+	wishlist := fs.String("wishlist", "", "comma separated item names to look out for, on top of the config's wishlist")
+	fs.Parse(args)
+
+	configs := append([]events.SinkConfig{}, config.Sinks...)
+	for sinkType, target := range map[string]string{events.SinkWebhook: *webhook, events.SinkDiscord: *discord, events.SinkSlack: *slack} {
+		if target != "" {
+			configs = append(configs, events.SinkConfig{Type: sinkType, URL: target})
+		}
+	}
+	if *command != "" {
+		configs = append(configs, events.SinkConfig{Type: events.SinkCommand, Command: *command})
+	}
+
+	sinks := []events.Sink{}
+	for _, sinkConfig := range configs {
+		sink, err := events.NewSink(sinkConfig)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) == 0 {
+		fmt.Println("No sinks configured, events are only printed. Use --webhook, --discord, --slack or --exec, or \"sinks\" in the config file.")
+	}
+
+	wishes := append(append([]string{}, config.Wishlist...), strings.Split(*wishlist, ",")...)
+
+	watcher, err := events.NewWatcher(getEventsStatePath(), wishes, sinks)
+	if err != nil {
+		return err
+	}
+
+	for {
+		var found []events.Event
+		err := withReauth(c, config, func() (err error) {
+			found, err = watcher.Poll(c)
+			return err
+		})
+		if err != nil {
+			if *once {
+				return err
+			}
+			log.Printf("could not check for events: %s", err)
+		}
+
+		for _, event := range found {
+			fmt.Printf("%s  %s: %s\n", event.Time.Format("15:04"), event.Title, event.Description)
+		}
+		if err := watcher.Dispatch(found); err != nil {
+			if *once {
+				return err
+			}
+			log.Println(err)
+		}
+
+		if *once {
+			return nil
+		}
+		time.Sleep(*interval)
+	}
+}
+
+func runExporter(c *core.Client, config AuthConfiguration, args []string) error {
+	fs := flag.NewFlagSet("exporter", flag.ExitOnError)
+	addr := fs.String("addr", metrics.DefaultAddr, "address to listen on")
+	interval := fs.Duration("interval", metrics.DefaultInterval, "how often player data is fetched")
+	profile := fs.String("profile", "", "profile label of the metrics (default your riot id)")
+	fs.Parse(args)
+
+	s := server.New(c, server.Options{
+		Addr:        *addr,
+		Refresh:     func() error { return refreshTokens(c, config, false) },
+		MetricsOnly: true,
+	})
+	serveMetrics(c, s, *profile, *interval)
+
+	return s.ListenAndServe()
+}
+
+// serveMetrics adds /metrics to the server and collects player data in the background
+func serveMetrics(c *core.Client, s *server.Server, profile string, interval time.Duration) {
+	collector := metrics.NewCollector(profile, func(fn func(c *core.Client) error) error {
+		_, err := s.Do(func(c *core.Client) (any, error) { return nil, fn(c) })
+		return err
+	})
+	collector.Instrument(c)
+
+	s.Handle("/metrics", collector)
+	go collector.Run(interval)
+}
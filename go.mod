@@ -2,11 +2,13 @@ module github.com/goamaan/valocli
 
 go 1.20
 
-require github.com/refraction-networking/utls v1.2.0
+require (
+	github.com/refraction-networking/utls v1.2.0
+	golang.org/x/sys v0.1.0
+)
 
 require (
 	github.com/andybalholm/brotli v1.0.4 // indirect
 	github.com/klauspost/compress v1.15.12 // indirect
 	golang.org/x/crypto v0.1.0 // indirect
-	golang.org/x/sys v0.1.0 // indirect
 )
@@ -0,0 +1,10 @@
+package chat
+
+import "errors"
+
+var (
+	ErrorPASTokenMalformed  = errors.New("pas_token_malformed_error")
+	ErrorChatAuthentication = errors.New("chat_authentication_error")
+	ErrorChatClosed         = errors.New("chat_closed_error")
+	ErrorFriendNotFound     = errors.New("friend_not_found_error")
+)
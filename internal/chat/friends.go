@@ -0,0 +1,156 @@
+package chat
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/goamaan/valocli/internal/content"
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/player"
+)
+
+type Friend struct {
+	PUUID    string
+	GameName string
+	TagLine  string
+	Online   bool
+	// chat, away, dnd or mobile, empty when offline
+	Show     string
+	Valorant *ValorantPresence
+}
+
+func (f Friend) RiotId() string {
+	return fmt.Sprintf("%s#%s", f.GameName, f.TagLine)
+}
+
+// Status describes what the friend is doing, preferring what the game reports
+func (f Friend) Status() string {
+	switch {
+	case !f.Online:
+		return "Offline"
+	case f.Valorant != nil:
+		return f.Valorant.State()
+	case f.Show == "mobile":
+		return "Mobile"
+	case f.Show == "away" || f.Show == "dnd":
+		return "Away"
+	default:
+		return "Online"
+	}
+}
+
+// Friends returns the friends list with everyone's latest presence, online friends first
+func (conn *Conn) Friends() []Friend {
+	conn.mu.Lock()
+	friends := []Friend{}
+	for puuid, item := range conn.roster {
+		friends = append(friends, conn.friend(puuid, item))
+	}
+	conn.mu.Unlock()
+
+	sort.Slice(friends, func(i, j int) bool {
+		if friends[i].Online != friends[j].Online {
+			return friends[i].Online
+		}
+		return strings.ToLower(friends[i].RiotId()) < strings.ToLower(friends[j].RiotId())
+	})
+
+	return friends
+}
+
+func (conn *Conn) friend(puuid string, item rosterItem) Friend {
+	friend := Friend{PUUID: puuid, GameName: item.ID.Name, TagLine: item.ID.TagLine}
+	for _, presence := range conn.presences[puuid] {
+		friend.Online = true
+		if friend.Show == "" || presence.Valorant != nil {
+			friend.Show = presence.Show
+		}
+		if presence.Valorant != nil {
+			friend.Valorant = presence.Valorant
+		}
+	}
+
+	return friend
+}
+
+// Friend finds a friend by riot id or puuid
+func (conn *Conn) Friend(riotId string) (Friend, error) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	for puuid, item := range conn.roster {
+		friend := conn.friend(puuid, item)
+		if puuid == riotId || strings.EqualFold(friend.RiotId(), riotId) {
+			return friend, nil
+		}
+	}
+
+	return Friend{}, ErrorFriendNotFound
+}
+
+// Name returns the riot id of a friend or of anyone else through the name service
+func (conn *Conn) Name(c *core.Client, puuid string) string {
+	conn.mu.Lock()
+	item, ok := conn.roster[puuid]
+	conn.mu.Unlock()
+
+	if ok {
+		return fmt.Sprintf("%s#%s", item.ID.Name, item.ID.TagLine)
+	}
+
+	return player.Names(c).Name(puuid)
+}
+
+func PrintFriends(friends []Friend) error {
+	return FprintFriends(os.Stdout, friends)
+}
+
+func FprintFriends(out io.Writer, friends []Friend) error {
+	tierMap, err := core.GetCompetitiveTiers()
+	if err != nil {
+		return err
+	}
+
+	online := 0
+	for _, friend := range friends {
+		if friend.Online {
+			online++
+		}
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintf(w, "🫂 Friends - %d/%d online 🫂\n", online, len(friends))
+	fmt.Fprintln(w, "Friend\tStatus\tFor\tQueue\tMap\tScore\tRank\tLevel\tParty")
+	for _, friend := range friends {
+		queue, mapName, score, rank, level, party := "", "", "", "", "", ""
+		if v := friend.Valorant; v != nil {
+			queue = player.QueueName(v.QueueID)
+			if v.SessionLoopState == StateInGame {
+				mapName, _ = content.MapName(v.MatchMap)
+				score = fmt.Sprintf("%d-%d", v.ScoreAlly, v.ScoreEnemy)
+			}
+			rank = tierMap[v.CompetitiveTier]
+			level = fmt.Sprint(v.AccountLevel)
+			party = fmt.Sprintf("%d/%d", v.PartySize, v.MaxPartySize)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			friend.RiotId(), friend.Status(), formatDuration(friend.TimeInState()), queue, mapName, score, rank, level, party)
+	}
+	w.Flush()
+
+	return nil
+}
+
+func PrintMessage(message Message, name string) {
+	where := ""
+	if message.Room != "" {
+		where = " (party)"
+	}
+
+	fmt.Printf("[%s] %s%s: %s\n", message.Time.Format("15:04"), name, where, message.Body)
+}
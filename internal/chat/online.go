@@ -0,0 +1,180 @@
+package chat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+)
+
+var SortKeys = []string{"name", "status", "time", "rank", "party"}
+
+// FriendFilter narrows down the friends list, zero values match everyone
+type FriendFilter struct {
+	Online bool
+	// menus, pregame, ingame or away
+	States  []string
+	Queue   string
+	NotFull bool
+}
+
+func (f FriendFilter) Match(friend Friend) bool {
+	if (f.Online || len(f.States) > 0 || f.Queue != "" || f.NotFull) && !friend.Online {
+		return false
+	}
+
+	v := friend.Valorant
+	if len(f.States) > 0 {
+		matched := false
+		for _, state := range f.States {
+			matched = matched || friend.inState(state)
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if f.Queue != "" && (v == nil || !strings.EqualFold(v.QueueID, f.Queue)) {
+		return false
+	}
+
+	if f.NotFull && (v == nil || v.MaxPartySize == 0 || v.PartySize >= v.MaxPartySize) {
+		return false
+	}
+
+	return true
+}
+
+func (friend Friend) inState(state string) bool {
+	v := friend.Valorant
+	switch strings.ToLower(state) {
+	case "away":
+		return friend.Status() == "Away"
+	case "menus":
+		return v != nil && !v.IsIdle && v.SessionLoopState == StateMenus
+	case "pregame":
+		return v != nil && !v.IsIdle && v.SessionLoopState == StatePregame
+	case "ingame":
+		return v != nil && !v.IsIdle && v.SessionLoopState == StateInGame
+	default:
+		return false
+	}
+}
+
+func FilterFriends(friends []Friend, filter FriendFilter) []Friend {
+	filtered := []Friend{}
+	for _, friend := range friends {
+		if filter.Match(friend) {
+			filtered = append(filtered, friend)
+		}
+	}
+
+	return filtered
+}
+
+// SortFriends orders friends by one of SortKeys, online friends always come first
+func SortFriends(friends []Friend, by string) error {
+	var less func(a, b Friend) bool
+	switch by {
+	case "name":
+		less = func(a, b Friend) bool { return false }
+	case "status":
+		less = func(a, b Friend) bool { return statusOrder(a) < statusOrder(b) }
+	case "time":
+		less = func(a, b Friend) bool { return a.TimeInState() > b.TimeInState() }
+	case "rank":
+		less = func(a, b Friend) bool { return valorantOf(a).CompetitiveTier > valorantOf(b).CompetitiveTier }
+	case "party":
+		less = func(a, b Friend) bool { return valorantOf(a).PartySize > valorantOf(b).PartySize }
+	default:
+		return fmt.Errorf("unknown sort %q, sort by one of: %s", by, strings.Join(SortKeys, ", "))
+	}
+
+	sort.SliceStable(friends, func(i, j int) bool {
+		a, b := friends[i], friends[j]
+		if a.Online != b.Online {
+			return a.Online
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return strings.ToLower(a.RiotId()) < strings.ToLower(b.RiotId())
+	})
+
+	return nil
+}
+
+// TimeInState is how long the friend has been in their current game state, zero if unknown
+func (friend Friend) TimeInState() time.Duration {
+	if friend.Valorant == nil || friend.Valorant.StateSince.IsZero() {
+		return 0
+	}
+
+	return time.Since(friend.Valorant.StateSince)
+}
+
+func statusOrder(friend Friend) int {
+	switch friend.Status() {
+	case "In menus":
+		return 0
+	case "Agent select":
+		return 1
+	case "In match":
+		return 2
+	case "Online":
+		return 3
+	case "Away", "Mobile":
+		return 4
+	default:
+		return 5
+	}
+}
+
+func valorantOf(friend Friend) ValorantPresence {
+	if friend.Valorant == nil {
+		return ValorantPresence{}
+	}
+
+	return *friend.Valorant
+}
+
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+
+	return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// GetFriends connects to chat, waits for presences to come in and prints the
+// friends matching the filter
+func GetFriends(c *core.Client, opts Options, wait time.Duration, filter FriendFilter, sortBy string) error {
+	// check the sort before spending time connecting
+	if err := SortFriends(nil, sortBy); err != nil {
+		return err
+	}
+
+	conn, err := Connect(c, opts)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	time.Sleep(wait)
+
+	friends := FilterFriends(conn.Friends(), filter)
+	if err = SortFriends(friends, sortBy); err != nil {
+		return err
+	}
+
+	return PrintFriends(friends)
+}
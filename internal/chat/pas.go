@@ -0,0 +1,100 @@
+package chat
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	PASTokenUrl = "https://riot-geo.pas.si.riotgames.com/pas/v1/service/chat"
+
+	ChatPort = 5223
+)
+
+// chat servers and xmpp domains per PAS affinity, affinities missing here use
+// {affinity}.chat.si.riotgames.com and {affinity}.pvp.net
+var (
+	affinityHosts = map[string]string{
+		"na1":  "na2",
+		"asia": "jp1",
+	}
+	affinityDomains = map[string]string{
+		"euw1": "eu1",
+		"eun1": "eu2",
+		"asia": "jp1",
+	}
+)
+
+type pasClaims struct {
+	Affinity string `json:"affinity"`
+}
+
+// FetchPASToken returns the player affinity service token riot chat requires next to the access token
+func FetchPASToken(c *core.Client) (string, error) {
+	req, err := c.RequestWithAuth("GET", PASTokenUrl, nil)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := c.HttpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusUnauthorized:
+		return "", core.ErrorRiotAuthentication
+	case res.StatusCode == http.StatusTooManyRequests:
+		return "", core.ErrorRiotRateLimit
+	case res.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("riot pas returned %s", res.Status)
+	}
+
+	token, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(token)), nil
+}
+
+// Affinity reads the chat affinity claim out of a PAS token
+func Affinity(pasToken string) (string, error) {
+	parts := strings.Split(pasToken, ".")
+	if len(parts) != 3 {
+		return "", ErrorPASTokenMalformed
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrorPASTokenMalformed
+	}
+
+	claims := new(pasClaims)
+	if err = json.Unmarshal(payload, claims); err != nil || claims.Affinity == "" {
+		return "", ErrorPASTokenMalformed
+	}
+
+	return claims.Affinity, nil
+}
+
+// Server returns the chat server address and xmpp domain for a PAS affinity
+func Server(affinity string) (string, string) {
+	host, domain := affinity, affinity
+	if h, ok := affinityHosts[affinity]; ok {
+		host = h
+	}
+	if d, ok := affinityDomains[affinity]; ok {
+		domain = d
+	}
+
+	return fmt.Sprintf("%s.chat.si.riotgames.com:%d", host, ChatPort), domain + ".pvp.net"
+}
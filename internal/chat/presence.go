@@ -0,0 +1,180 @@
+package chat
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+const (
+	StateMenus   = "MENUS"
+	StatePregame = "PREGAME"
+	StateInGame  = "INGAME"
+)
+
+type presenceStanza struct {
+	From  string `xml:"from,attr"`
+	Type  string `xml:"type,attr"`
+	Show  string `xml:"show"`
+	Games struct {
+		Valorant *struct {
+			State     string `xml:"st"`
+			Timestamp int64  `xml:"s.t"`
+			Private   string `xml:"p"`
+		} `xml:"valorant"`
+	} `xml:"games"`
+}
+
+// Presence is what a single resource (riot client, game) of a friend reports
+type Presence struct {
+	// chat, away, dnd or mobile
+	Show     string
+	Valorant *ValorantPresence
+}
+
+type ValorantPresence struct {
+	SessionLoopState   string
+	QueueID            string
+	MatchMap           string
+	ScoreAlly          int
+	ScoreEnemy         int
+	CompetitiveTier    int
+	AccountLevel       int
+	PartyID            string
+	PartySize          int
+	MaxPartySize       int
+	PartyAccessibility string
+	IsIdle             bool
+	UpdatedAt          time.Time
+	// when the player got into their current state, zero until we've seen them change state
+	// as the game doesn't say when the current one started
+	StateSince time.Time
+}
+
+// the game has sent two shapes of private presence, the older one has every field at the
+// top level and the newer one nests them per topic
+type privatePresence struct {
+	flatPresence
+
+	PlayerPresenceData *flatPresence `json:"playerPresenceData"`
+	MatchPresenceData  *flatPresence `json:"matchPresenceData"`
+	PartyPresenceData  *flatPresence `json:"partyPresenceData"`
+}
+
+type flatPresence struct {
+	IsIdle                        bool   `json:"isIdle"`
+	SessionLoopState              string `json:"sessionLoopState"`
+	PartyOwnerSessionLoopState    string `json:"partyOwnerSessionLoopState"`
+	QueueID                       string `json:"queueId"`
+	MatchMap                      string `json:"matchMap"`
+	PartyOwnerMatchMap            string `json:"partyOwnerMatchMap"`
+	PartyOwnerMatchScoreAllyTeam  int    `json:"partyOwnerMatchScoreAllyTeam"`
+	PartyOwnerMatchScoreEnemyTeam int    `json:"partyOwnerMatchScoreEnemyTeam"`
+	CompetitiveTier               int    `json:"competitiveTier"`
+	AccountLevel                  int    `json:"accountLevel"`
+	PartyID                       string `json:"partyId"`
+	PartySize                     int    `json:"partySize"`
+	MaxPartySize                  int    `json:"maxPartySize"`
+	PartyAccessibility            string `json:"partyAccessibility"`
+}
+
+func (stanza *presenceStanza) decode() Presence {
+	presence := Presence{Show: stanza.Show}
+	if presence.Show == "" {
+		presence.Show = "chat"
+	}
+
+	game := stanza.Games.Valorant
+	if game == nil || game.Private == "" {
+		return presence
+	}
+
+	data, err := base64.StdEncoding.DecodeString(game.Private)
+	if err != nil {
+		return presence
+	}
+
+	private := new(privatePresence)
+	if err = json.Unmarshal(data, private); err != nil {
+		return presence
+	}
+
+	merged := private.flatPresence
+	for _, part := range []*flatPresence{private.PlayerPresenceData, private.MatchPresenceData, private.PartyPresenceData} {
+		if part != nil {
+			merged.merge(part)
+		}
+	}
+
+	valorant := &ValorantPresence{
+		SessionLoopState:   merged.SessionLoopState,
+		QueueID:            merged.QueueID,
+		MatchMap:           merged.MatchMap,
+		ScoreAlly:          merged.PartyOwnerMatchScoreAllyTeam,
+		ScoreEnemy:         merged.PartyOwnerMatchScoreEnemyTeam,
+		CompetitiveTier:    merged.CompetitiveTier,
+		AccountLevel:       merged.AccountLevel,
+		PartyID:            merged.PartyID,
+		PartySize:          merged.PartySize,
+		MaxPartySize:       merged.MaxPartySize,
+		PartyAccessibility: merged.PartyAccessibility,
+		IsIdle:             merged.IsIdle,
+		UpdatedAt:          time.UnixMilli(game.Timestamp),
+	}
+
+	// the party owner's state is what matters to someone waiting on the party
+	if valorant.SessionLoopState == "" {
+		valorant.SessionLoopState = merged.PartyOwnerSessionLoopState
+	}
+	if valorant.MatchMap == "" {
+		valorant.MatchMap = merged.PartyOwnerMatchMap
+	}
+
+	presence.Valorant = valorant
+	return presence
+}
+
+func (p *flatPresence) merge(other *flatPresence) {
+	p.IsIdle = p.IsIdle || other.IsIdle
+	mergeString(&p.SessionLoopState, other.SessionLoopState)
+	mergeString(&p.PartyOwnerSessionLoopState, other.PartyOwnerSessionLoopState)
+	mergeString(&p.QueueID, other.QueueID)
+	mergeString(&p.MatchMap, other.MatchMap)
+	mergeString(&p.PartyOwnerMatchMap, other.PartyOwnerMatchMap)
+	mergeString(&p.PartyID, other.PartyID)
+	mergeString(&p.PartyAccessibility, other.PartyAccessibility)
+	mergeInt(&p.PartyOwnerMatchScoreAllyTeam, other.PartyOwnerMatchScoreAllyTeam)
+	mergeInt(&p.PartyOwnerMatchScoreEnemyTeam, other.PartyOwnerMatchScoreEnemyTeam)
+	mergeInt(&p.CompetitiveTier, other.CompetitiveTier)
+	mergeInt(&p.AccountLevel, other.AccountLevel)
+	mergeInt(&p.PartySize, other.PartySize)
+	mergeInt(&p.MaxPartySize, other.MaxPartySize)
+}
+
+func mergeString(dst *string, src string) {
+	if src != "" {
+		*dst = src
+	}
+}
+
+func mergeInt(dst *int, src int) {
+	if src != 0 {
+		*dst = src
+	}
+}
+
+// State describes what the player is doing in a few words
+func (v *ValorantPresence) State() string {
+	switch {
+	case v.IsIdle:
+		return "Away"
+	case v.SessionLoopState == StateMenus:
+		return "In menus"
+	case v.SessionLoopState == StatePregame:
+		return "Agent select"
+	case v.SessionLoopState == StateInGame:
+		return "In match"
+	default:
+		return "Online"
+	}
+}
@@ -0,0 +1,492 @@
+package chat
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	Resource = "RC-valocli"
+
+	handshakeTimeout  = 15 * time.Second
+	keepaliveInterval = 150 * time.Second
+
+	rosterNamespace = "jabber:iq:riotgames:roster"
+)
+
+// Options overrides where and how to connect, which is mostly useful to point valocli
+// at a local stand-in server. Empty fields are filled in from the PAS token.
+type Options struct {
+	Address  string
+	Domain   string
+	PASToken string
+
+	TLSConfig *tls.Config
+}
+
+type Message struct {
+	From string
+	// set for party messages, the party chat room the message was sent in
+	Room string
+	Body string
+	Time time.Time
+}
+
+// Conn is an authenticated connection to riot chat, it keeps the roster and the latest
+// presences of friends up to date until it's closed
+type Conn struct {
+	Domain string
+	JID    string
+	PUUID  string
+
+	conn    net.Conn
+	dec     *xml.Decoder
+	writeMu sync.Mutex
+
+	mu        sync.Mutex
+	roster    map[string]rosterItem
+	presences map[string]map[string]Presence
+	err       error
+
+	messages chan Message
+	updates  chan string
+	done     chan struct{}
+}
+
+type streamFeatures struct {
+	Mechanisms []string `xml:"mechanisms>mechanism"`
+}
+
+type iqStanza struct {
+	ID    string `xml:"id,attr"`
+	Type  string `xml:"type,attr"`
+	From  string `xml:"from,attr"`
+	Inner []byte `xml:",innerxml"`
+	Bind  struct {
+		JID string `xml:"jid"`
+	} `xml:"bind"`
+	Ping  *struct{} `xml:"urn:xmpp:ping ping"`
+	Query struct {
+		Items []rosterItem `xml:"item"`
+	} `xml:"query"`
+}
+
+type rosterItem struct {
+	JID          string `xml:"jid,attr"`
+	PUUID        string `xml:"puuid,attr"`
+	Subscription string `xml:"subscription,attr"`
+	ID           struct {
+		Name    string `xml:"name,attr"`
+		TagLine string `xml:"tagline,attr"`
+	} `xml:"id"`
+}
+
+type messageStanza struct {
+	ID    string `xml:"id,attr"`
+	From  string `xml:"from,attr"`
+	Type  string `xml:"type,attr"`
+	Stamp string `xml:"stamp,attr"`
+	Body  string `xml:"body"`
+}
+
+// Connect logs into riot chat with the client's tokens and loads the friends list
+func Connect(c *core.Client, opts Options) (*Conn, error) {
+	if opts.PASToken == "" {
+		token, err := FetchPASToken(c)
+		if err != nil {
+			return nil, err
+		}
+		opts.PASToken = token
+	}
+
+	if opts.Address == "" || opts.Domain == "" {
+		affinity, err := Affinity(opts.PASToken)
+		if err != nil {
+			return nil, err
+		}
+
+		address, domain := Server(affinity)
+		if opts.Address == "" {
+			opts.Address = address
+		}
+		if opts.Domain == "" {
+			opts.Domain = domain
+		}
+	}
+
+	tlsConfig := opts.TLSConfig
+	if tlsConfig == nil {
+		host, _, err := net.SplitHostPort(opts.Address)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = &tls.Config{ServerName: host}
+	}
+
+	netConn, err := tls.DialWithDialer(&net.Dialer{Timeout: handshakeTimeout}, "tcp", opts.Address, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &Conn{
+		Domain:    opts.Domain,
+		conn:      netConn,
+		roster:    map[string]rosterItem{},
+		presences: map[string]map[string]Presence{},
+		messages:  make(chan Message, 64),
+		updates:   make(chan string, 64),
+		done:      make(chan struct{}),
+	}
+
+	netConn.SetDeadline(time.Now().Add(handshakeTimeout))
+	if err = conn.handshake(c, opts.PASToken); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	netConn.SetDeadline(time.Time{})
+
+	go conn.readLoop()
+	go conn.keepalive()
+
+	return conn, nil
+}
+
+func (conn *Conn) handshake(c *core.Client, pasToken string) error {
+	if _, err := conn.openStream(); err != nil {
+		return err
+	}
+
+	err := conn.write(`<auth mechanism="X-Riot-RSO-PAS" xmlns="urn:ietf:params:xml:ns:xmpp-sasl"><rso_token>%s</rso_token><pas_token>%s</pas_token></auth>`,
+		escape(c.AuthData.AuthTokens.AccessToken), escape(pasToken))
+	if err != nil {
+		return err
+	}
+
+	start, err := conn.nextStart()
+	if err != nil {
+		return err
+	}
+	if err = conn.dec.Skip(); err != nil {
+		return err
+	}
+	if start.Name.Local != "success" {
+		return ErrorChatAuthentication
+	}
+
+	// the stream starts over once authenticated
+	if _, err = conn.openStream(); err != nil {
+		return err
+	}
+
+	bind, err := conn.iq("bind", `<iq id="bind" type="set"><bind xmlns="urn:ietf:params:xml:ns:xmpp-bind"><puuid-mode xmlns="urn:riotgames:rso" enabled="true"/><resource>%s</resource></bind></iq>`, Resource)
+	if err != nil {
+		return err
+	}
+	conn.JID = bind.Bind.JID
+	conn.PUUID = puuidOf(conn.JID)
+
+	_, err = conn.iq("entitlements", `<iq id="entitlements" type="set"><entitlements xmlns="urn:riotgames:entitlements"><token xmlns="">%s</token></entitlements></iq>`, escape(c.AuthData.EntitlementToken))
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.iq("session", `<iq id="session" type="set"><session xmlns="urn:ietf:params:xml:ns:xmpp-session"><platform>riot</platform></session></iq>`)
+	if err != nil {
+		return err
+	}
+
+	roster, err := conn.iq("roster", `<iq id="roster" type="get"><query xmlns="%s" last_state="true"/></iq>`, rosterNamespace)
+	if err != nil {
+		return err
+	}
+	for _, item := range roster.Query.Items {
+		if item.Subscription != "both" {
+			continue
+		}
+		if item.PUUID == "" {
+			item.PUUID = puuidOf(item.JID)
+		}
+		conn.roster[item.PUUID] = item
+	}
+
+	// friends only send us their presence once we've sent ours
+	return conn.write(`<presence/>`)
+}
+
+func (conn *Conn) openStream() (*streamFeatures, error) {
+	conn.dec = xml.NewDecoder(conn.conn)
+	err := conn.write(`<?xml version="1.0"?><stream:stream to="%s" version="1.0" xmlns:stream="http://etherx.jabber.org/streams">`, escape(conn.Domain))
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		token, err := conn.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "stream" {
+			break
+		}
+	}
+
+	start, err := conn.nextStart()
+	if err != nil {
+		return nil, err
+	}
+
+	features := new(streamFeatures)
+	if err = conn.dec.DecodeElement(features, &start); err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
+
+// nextStart returns the start of the next top level stanza
+func (conn *Conn) nextStart() (xml.StartElement, error) {
+	for {
+		token, err := conn.dec.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			return t, nil
+		case xml.EndElement:
+			return xml.StartElement{}, io.EOF
+		}
+	}
+}
+
+// iq sends a request and waits for its result, handling anything that arrives in between
+func (conn *Conn) iq(id, format string, args ...any) (*iqStanza, error) {
+	if err := conn.write(format, args...); err != nil {
+		return nil, err
+	}
+
+	for {
+		iq, err := conn.readStanza()
+		if err != nil {
+			return nil, err
+		}
+		if iq == nil || iq.ID != id {
+			continue
+		}
+		if iq.Type == "error" {
+			return nil, fmt.Errorf("riot chat rejected %s: %s", id, iq.Inner)
+		}
+
+		return iq, nil
+	}
+}
+
+// readStanza reads and handles the next stanza, returning it if it's an iq
+func (conn *Conn) readStanza() (*iqStanza, error) {
+	start, err := conn.nextStart()
+	if err != nil {
+		return nil, err
+	}
+
+	switch start.Name.Local {
+	case "iq":
+		iq := new(iqStanza)
+		if err = conn.dec.DecodeElement(iq, &start); err != nil {
+			return nil, err
+		}
+		if iq.Type == "get" && iq.Ping != nil {
+			return iq, conn.write(`<iq type="result" id="%s" to="%s"/>`, escape(iq.ID), escape(iq.From))
+		}
+		return iq, nil
+	case "presence":
+		presence := new(presenceStanza)
+		if err = conn.dec.DecodeElement(presence, &start); err != nil {
+			return nil, err
+		}
+		conn.handlePresence(presence)
+	case "message":
+		message := new(messageStanza)
+		if err = conn.dec.DecodeElement(message, &start); err != nil {
+			return nil, err
+		}
+		conn.handleMessage(message)
+	default:
+		if err = conn.dec.Skip(); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+func (conn *Conn) readLoop() {
+	defer close(conn.done)
+
+	for {
+		if _, err := conn.readStanza(); err != nil {
+			if err == io.EOF {
+				err = ErrorChatClosed
+			}
+
+			conn.mu.Lock()
+			conn.err = err
+			conn.mu.Unlock()
+			return
+		}
+	}
+}
+
+// keepalive sends whitespace every so often, riot drops connections that stay quiet
+func (conn *Conn) keepalive() {
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-conn.done:
+			return
+		case <-ticker.C:
+			conn.write(" ")
+		}
+	}
+}
+
+func (conn *Conn) handlePresence(stanza *presenceStanza) {
+	puuid, resource := puuidOf(stanza.From), resourceOf(stanza.From)
+	if puuid == conn.PUUID {
+		return
+	}
+
+	conn.mu.Lock()
+	if stanza.Type == "unavailable" {
+		delete(conn.presences[puuid], resource)
+	} else {
+		if conn.presences[puuid] == nil {
+			conn.presences[puuid] = map[string]Presence{}
+		}
+		presence := stanza.decode()
+		if previous, ok := conn.presences[puuid][resource]; ok && presence.Valorant != nil && previous.Valorant != nil {
+			if previous.Valorant.State() == presence.Valorant.State() {
+				presence.Valorant.StateSince = previous.Valorant.StateSince
+			} else {
+				presence.Valorant.StateSince = presence.Valorant.UpdatedAt
+			}
+		}
+		conn.presences[puuid][resource] = presence
+	}
+	conn.mu.Unlock()
+
+	select {
+	case conn.updates <- puuid:
+	default:
+	}
+}
+
+func (conn *Conn) handleMessage(stanza *messageStanza) {
+	if stanza.Body == "" {
+		return
+	}
+
+	message := Message{From: puuidOf(stanza.From), Body: stanza.Body, Time: time.Now()}
+	if stanza.Type == "groupchat" {
+		message.Room, message.From = bareOf(stanza.From), resourceOf(stanza.From)
+	}
+	if stamp, err := time.Parse("2006-01-02 15:04:05.000", stanza.Stamp); err == nil {
+		message.Time = stamp.Local()
+	}
+
+	select {
+	case conn.messages <- message:
+	default:
+		log.Printf("dropped chat message from %s, too many unread messages", message.From)
+	}
+}
+
+// Messages delivers incoming direct and party messages
+func (conn *Conn) Messages() <-chan Message {
+	return conn.messages
+}
+
+// Updates delivers the puuid of a friend whenever their presence changes
+func (conn *Conn) Updates() <-chan string {
+	return conn.updates
+}
+
+// Done is closed when the connection is lost, Err then returns why
+func (conn *Conn) Done() <-chan struct{} {
+	return conn.done
+}
+
+func (conn *Conn) Err() error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	return conn.err
+}
+
+// Send sends a direct message to a friend
+func (conn *Conn) Send(puuid, body string) error {
+	return conn.write(`<message id="%d" to="%s@%s" type="chat"><body>%s</body></message>`,
+		time.Now().UnixMilli(), escape(puuid), escape(conn.Domain), escape(body))
+}
+
+// PartyRoom returns the chat room of a party from its MUC name
+func (conn *Conn) PartyRoom(mucName string) string {
+	return fmt.Sprintf("%s@ares-parties.%s", mucName, conn.Domain)
+}
+
+// JoinRoom joins a chat room so its messages are delivered
+func (conn *Conn) JoinRoom(room string) error {
+	return conn.write(`<presence to="%s/%s"><x xmlns="http://jabber.org/protocol/muc"/></presence>`, escape(room), escape(conn.PUUID))
+}
+
+// SendRoom sends a message to a chat room that has been joined
+func (conn *Conn) SendRoom(room, body string) error {
+	return conn.write(`<message id="%d" to="%s" type="groupchat"><body>%s</body></message>`,
+		time.Now().UnixMilli(), escape(room), escape(body))
+}
+
+func (conn *Conn) Close() error {
+	conn.write(`</stream:stream>`)
+	return conn.conn.Close()
+}
+
+func (conn *Conn) write(format string, args ...any) error {
+	conn.writeMu.Lock()
+	defer conn.writeMu.Unlock()
+
+	_, err := fmt.Fprintf(conn.conn, format, args...)
+	return err
+}
+
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+func bareOf(jid string) string {
+	bare, _, _ := strings.Cut(jid, "/")
+	return bare
+}
+
+func puuidOf(jid string) string {
+	puuid, _, _ := strings.Cut(jid, "@")
+	return puuid
+}
+
+func resourceOf(jid string) string {
+	_, resource, _ := strings.Cut(jid, "/")
+	return resource
+}
@@ -0,0 +1,456 @@
+package chat
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	testDomain = "eu1.pvp.net"
+	selfPuuid  = "11111111-1111-1111-1111-111111111111"
+	alicePuuid = "22222222-2222-2222-2222-222222222222"
+	bobPuuid   = "33333333-3333-3333-3333-333333333333"
+	evePuuid   = "44444444-4444-4444-4444-444444444444"
+)
+
+// standIn plays the riot chat server for one connection
+type standIn struct {
+	t    *testing.T
+	conn net.Conn
+	dec  *xml.Decoder
+}
+
+type element struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   string     `xml:",innerxml"`
+}
+
+func (e element) attr(name string) string {
+	for _, attr := range e.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+func (s *standIn) write(format string, args ...any) {
+	if _, err := fmt.Fprintf(s.conn, format, args...); err != nil {
+		s.t.Errorf("stand-in write: %s", err)
+	}
+}
+
+// openStream waits for the client to open a stream and answers with features
+func (s *standIn) openStream(features string) {
+	s.dec = xml.NewDecoder(s.conn)
+	for {
+		token, err := s.dec.Token()
+		if err != nil {
+			s.t.Errorf("stand-in waiting for stream: %s", err)
+			return
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "stream" {
+			break
+		}
+	}
+
+	s.write(`<?xml version="1.0"?><stream:stream xmlns="jabber:client" xmlns:stream="http://etherx.jabber.org/streams" from="%s" version="1.0"><stream:features>%s</stream:features>`, testDomain, features)
+}
+
+// next reads the next stanza the client sent
+func (s *standIn) next() element {
+	for {
+		token, err := s.dec.Token()
+		if err != nil {
+			s.t.Errorf("stand-in reading: %s", err)
+			return element{}
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			var e element
+			if err = s.dec.DecodeElement(&e, &start); err != nil {
+				s.t.Errorf("stand-in decoding %s: %s", start.Name.Local, err)
+			}
+			return e
+		}
+	}
+}
+
+// waitClosed reads until the client hangs up
+func (s *standIn) waitClosed() {
+	for {
+		if _, err := s.dec.Token(); err != nil {
+			return
+		}
+	}
+}
+
+// expect reads the next stanza and checks its name and id
+func (s *standIn) expect(name, id string) element {
+	e := s.next()
+	if e.XMLName.Local != name || e.attr("id") != id {
+		s.t.Errorf("stand-in got <%s id=%q>, want <%s id=%q>", e.XMLName.Local, e.attr("id"), name, id)
+	}
+
+	return e
+}
+
+// handshake answers the client's login the way riot chat does, with a roster of two friends
+// and a pending request
+func (s *standIn) handshake() {
+	s.openStream(`<mechanisms xmlns="urn:ietf:params:xml:ns:xmpp-sasl"><mechanism>X-Riot-RSO-PAS</mechanism></mechanisms>`)
+
+	auth := s.next()
+	if auth.XMLName.Local != "auth" || auth.attr("mechanism") != "X-Riot-RSO-PAS" ||
+		!strings.Contains(auth.Inner, "<rso_token>access</rso_token>") || !strings.Contains(auth.Inner, "<pas_token>pas</pas_token>") {
+		s.t.Errorf("unexpected auth: %+v", auth)
+	}
+	s.write(`<success xmlns="urn:ietf:params:xml:ns:xmpp-sasl"/>`)
+
+	s.openStream(`<bind xmlns="urn:ietf:params:xml:ns:xmpp-bind"/><session xmlns="urn:ietf:params:xml:ns:xmpp-session"/>`)
+	s.expect("iq", "bind")
+	s.write(`<iq id="bind" type="result"><bind xmlns="urn:ietf:params:xml:ns:xmpp-bind"><jid>%s@%s/%s</jid></bind></iq>`, selfPuuid, testDomain, Resource)
+
+	if entitlements := s.expect("iq", "entitlements"); !strings.Contains(entitlements.Inner, "entitlement-token") {
+		s.t.Errorf("entitlements iq without the token: %s", entitlements.Inner)
+	}
+	s.write(`<iq id="entitlements" type="result"/>`)
+
+	s.expect("iq", "session")
+	s.write(`<iq id="session" type="result"/>`)
+
+	s.expect("iq", "roster")
+	s.write(`<iq id="roster" type="result"><query xmlns="%s">`+
+		`<item jid="%s@%s" puuid="%s" subscription="both"><id name="Alice" tagline="EUW"/></item>`+
+		`<item jid="%s@%s" subscription="both"><id name="bob" tagline="0001"/></item>`+
+		`<item jid="%s@%s" puuid="%s" subscription="pending_out"><id name="Eve" tagline="666"/></item>`+
+		`</query></iq>`,
+		rosterNamespace, alicePuuid, testDomain, alicePuuid, bobPuuid, testDomain, evePuuid, testDomain, evePuuid)
+
+	if presence := s.next(); presence.XMLName.Local != "presence" {
+		s.t.Errorf("expected the initial presence, got %s", presence.XMLName.Local)
+	}
+}
+
+// startStandIn runs serve against the first connection and returns options to reach it
+func startStandIn(t *testing.T, serve func(s *standIn)) Options {
+	t.Helper()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{selfSignedCert(t)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		listener.Close()
+		<-done
+	})
+
+	go func() {
+		defer close(done)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		serve(&standIn{t: t, conn: conn})
+	}()
+
+	return Options{
+		Address:   listener.Addr().String(),
+		Domain:    testDomain,
+		PASToken:  "pas",
+		TLSConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+}
+
+func selfSignedCert(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "chat stand-in"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func testClient() *core.Client {
+	c := core.New(nil)
+	c.AuthData.AuthTokens.AccessToken = "access"
+	c.AuthData.EntitlementToken = "entitlement-token"
+	return c
+}
+
+func connect(t *testing.T, opts Options) *Conn {
+	t.Helper()
+
+	conn, err := Connect(testClient(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func privatePresenceXML(from, state string, private string) string {
+	return privatePresenceXMLAt(from, state, private, time.Now())
+}
+
+func privatePresenceXMLAt(from, state string, private string, at time.Time) string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(private))
+	return fmt.Sprintf(`<presence from="%s"><show>chat</show><games><valorant><st>%s</st><s.t>%d</s.t><p>%s</p></valorant></games></presence>`,
+		from, state, at.UnixMilli(), encoded)
+}
+
+func waitFor[T any](t *testing.T, ch <-chan T, what string) T {
+	t.Helper()
+
+	select {
+	case value := <-ch:
+		return value
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+		var zero T
+		return zero
+	}
+}
+
+func TestConnect(t *testing.T) {
+	opts := startStandIn(t, func(s *standIn) {
+		s.handshake()
+		s.waitClosed()
+	})
+
+	conn := connect(t, opts)
+	if conn.PUUID != selfPuuid || conn.JID != fmt.Sprintf("%s@%s/%s", selfPuuid, testDomain, Resource) {
+		t.Errorf("bound as %s (%s)", conn.JID, conn.PUUID)
+	}
+
+	friends := conn.Friends()
+	if len(friends) != 2 {
+		t.Fatalf("got %d friends, want 2 (pending requests aren't friends): %+v", len(friends), friends)
+	}
+	if friends[0].RiotId() != "Alice#EUW" || friends[1].RiotId() != "bob#0001" {
+		t.Errorf("got friends %s and %s", friends[0].RiotId(), friends[1].RiotId())
+	}
+	if friends[1].PUUID != bobPuuid {
+		t.Errorf("puuid not taken from the jid: %s", friends[1].PUUID)
+	}
+	for _, friend := range friends {
+		if friend.Online || friend.Status() != "Offline" {
+			t.Errorf("%s is %s before any presence", friend.RiotId(), friend.Status())
+		}
+	}
+
+	if _, err := conn.Friend("eve#666"); !errors.Is(err, ErrorFriendNotFound) {
+		t.Errorf("Friend(eve#666) = %v, want ErrorFriendNotFound", err)
+	}
+	if friend, err := conn.Friend("alice#euw"); err != nil || friend.PUUID != alicePuuid {
+		t.Errorf("Friend(alice#euw) = %+v, %v", friend, err)
+	}
+}
+
+func TestConnectRejected(t *testing.T) {
+	opts := startStandIn(t, func(s *standIn) {
+		s.openStream(`<mechanisms xmlns="urn:ietf:params:xml:ns:xmpp-sasl"><mechanism>X-Riot-RSO-PAS</mechanism></mechanisms>`)
+		s.next()
+		s.write(`<failure xmlns="urn:ietf:params:xml:ns:xmpp-sasl"><not-authorized/></failure>`)
+	})
+
+	if _, err := Connect(testClient(), opts); !errors.Is(err, ErrorChatAuthentication) {
+		t.Errorf("got %v, want ErrorChatAuthentication", err)
+	}
+}
+
+func TestPresenceUpdates(t *testing.T) {
+	sent := make(chan struct{})
+	opts := startStandIn(t, func(s *standIn) {
+		s.handshake()
+
+		// the older flat presence from alice's game, the newer nested one from bob's
+		s.write(privatePresenceXML(alicePuuid+"@"+testDomain+"/RC-1", "dnd",
+			`{"sessionLoopState":"INGAME","queueId":"competitive","matchMap":"/Game/Maps/Ascent/Ascent","partyOwnerMatchScoreAllyTeam":7,"partyOwnerMatchScoreEnemyTeam":5,"competitiveTier":12,"partySize":2,"maxPartySize":5}`))
+		s.write(privatePresenceXML(bobPuuid+"@"+testDomain+"/RC-2", "chat",
+			`{"isIdle":false,"matchPresenceData":{"sessionLoopState":"MENUS","queueId":"unrated"},"partyPresenceData":{"partySize":1,"maxPartySize":5},"playerPresenceData":{"competitiveTier":20,"accountLevel":150}}`))
+		// our own presence is not a friend's
+		s.write(`<presence from="%s@%s/RC-3"/>`, selfPuuid, testDomain)
+		<-sent
+
+		s.write(`<presence from="%s@%s/RC-1" type="unavailable"/>`, alicePuuid, testDomain)
+		s.waitClosed()
+	})
+
+	conn := connect(t, opts)
+	updated := map[string]bool{}
+	for len(updated) < 2 {
+		updated[waitFor(t, conn.Updates(), "presence updates")] = true
+	}
+	if !updated[alicePuuid] || !updated[bobPuuid] {
+		t.Errorf("updates for %v", updated)
+	}
+
+	alice, err := conn.Friend(alicePuuid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !alice.Online || alice.Status() != "In match" {
+		t.Errorf("alice is %s", alice.Status())
+	}
+	if v := alice.Valorant; v.QueueID != "competitive" || v.ScoreAlly != 7 || v.ScoreEnemy != 5 || v.CompetitiveTier != 12 || v.PartySize != 2 {
+		t.Errorf("alice's presence: %+v", v)
+	}
+
+	bob, err := conn.Friend("bob#0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bob.Status() != "In menus" || bob.Valorant.QueueID != "unrated" || bob.Valorant.AccountLevel != 150 || bob.Valorant.CompetitiveTier != 20 {
+		t.Errorf("bob's presence: %s %+v", bob.Status(), bob.Valorant)
+	}
+
+	close(sent)
+	if puuid := waitFor(t, conn.Updates(), "alice going offline"); puuid != alicePuuid {
+		t.Errorf("update for %s, want alice", puuid)
+	}
+	if alice, _ = conn.Friend(alicePuuid); alice.Online {
+		t.Error("alice still online after unavailable presence")
+	}
+}
+
+func TestStateSince(t *testing.T) {
+	from := alicePuuid + "@" + testDomain + "/RC-1"
+	queued := time.Now().Add(-20 * time.Minute)
+	started := time.Now().Add(-15 * time.Minute)
+
+	steps := make(chan struct{})
+	opts := startStandIn(t, func(s *standIn) {
+		s.handshake()
+
+		// presences are sent again whenever anything changes, not only the state
+		s.write(privatePresenceXMLAt(from, "chat", `{"sessionLoopState":"MENUS","partySize":1}`, queued))
+		<-steps
+		s.write(privatePresenceXMLAt(from, "chat", `{"sessionLoopState":"MENUS","partySize":2}`, queued.Add(time.Minute)))
+		<-steps
+		s.write(privatePresenceXMLAt(from, "dnd", `{"sessionLoopState":"INGAME","partySize":2}`, started))
+		<-steps
+		s.write(privatePresenceXMLAt(from, "dnd", `{"sessionLoopState":"INGAME","partySize":2,"partyOwnerMatchScoreAllyTeam":1}`, started.Add(2*time.Minute)))
+		s.waitClosed()
+	})
+
+	conn := connect(t, opts)
+	since := func() time.Time {
+		waitFor(t, conn.Updates(), "a presence update")
+		alice, err := conn.Friend(alicePuuid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return alice.Valorant.StateSince
+	}
+
+	if at := since(); !at.IsZero() {
+		t.Errorf("state of the first presence started at %s, the game doesn't say when", at)
+	}
+	steps <- struct{}{}
+	if at := since(); !at.IsZero() {
+		t.Errorf("still in menus but the state started at %s", at)
+	}
+	steps <- struct{}{}
+	if at := since(); !at.Equal(started.Truncate(time.Millisecond)) {
+		t.Errorf("match started at %s, want %s", at, started)
+	}
+	steps <- struct{}{}
+	if at := since(); !at.Equal(started.Truncate(time.Millisecond)) {
+		t.Errorf("score update moved the state start to %s", at)
+	}
+}
+
+func TestMessages(t *testing.T) {
+	received := make(chan element, 1)
+	opts := startStandIn(t, func(s *standIn) {
+		s.handshake()
+
+		s.write(`<message from="%s@%s/RC-1" type="chat" stamp="2024-05-01 18:30:00.000"><body>gg &amp; wp</body></message>`, alicePuuid, testDomain)
+		s.write(`<message from="party-1@ares-parties.%s/%s" type="groupchat"><body>ready?</body></message>`, testDomain, bobPuuid)
+		// typing notifications have no body and aren't messages
+		s.write(`<message from="%s@%s/RC-1" type="chat"><composing/></message>`, alicePuuid, testDomain)
+
+		received <- s.next()
+		s.waitClosed()
+	})
+
+	conn := connect(t, opts)
+
+	direct := waitFor(t, conn.Messages(), "a direct message")
+	if direct.From != alicePuuid || direct.Body != "gg & wp" || direct.Room != "" {
+		t.Errorf("direct message: %+v", direct)
+	}
+	if stamp := direct.Time.UTC().Format("2006-01-02 15:04"); stamp != "2024-05-01 18:30" {
+		t.Errorf("message time %s, want the stamp", stamp)
+	}
+
+	party := waitFor(t, conn.Messages(), "a party message")
+	if party.Room != "party-1@ares-parties."+testDomain || party.From != bobPuuid || party.Body != "ready?" {
+		t.Errorf("party message: %+v", party)
+	}
+
+	if err := conn.Send(alicePuuid, "see you <3"); err != nil {
+		t.Fatal(err)
+	}
+	sent := waitFor(t, received, "the sent message")
+	if sent.XMLName.Local != "message" || sent.attr("to") != alicePuuid+"@"+testDomain || sent.attr("type") != "chat" ||
+		!strings.Contains(sent.Inner, "<body>see you &lt;3</body>") {
+		t.Errorf("sent %+v", sent)
+	}
+
+	select {
+	case message := <-conn.Messages():
+		t.Errorf("unexpected message %+v", message)
+	default:
+	}
+}
+
+func TestConnectionClosed(t *testing.T) {
+	opts := startStandIn(t, func(s *standIn) {
+		s.handshake()
+		s.write(`</stream:stream>`)
+	})
+
+	conn := connect(t, opts)
+	waitFor(t, conn.Done(), "the connection to close")
+	if !errors.Is(conn.Err(), ErrorChatClosed) {
+		t.Errorf("Err() = %v, want ErrorChatClosed", conn.Err())
+	}
+}
@@ -0,0 +1,237 @@
+package content
+
+import "fmt"
+
+const (
+	MapsUrl   = BaseUrl + "/maps"
+	AgentsUrl = BaseUrl + "/agents/%s"
+)
+
+type Map struct {
+	UUID         string `json:"uuid"`
+	DisplayName  string `json:"displayName"`
+	MapUrl       string `json:"mapUrl"`
+	ListViewIcon string `json:"listViewIcon"`
+	Splash       string `json:"splash"`
+}
+
+type Agent struct {
+	UUID        string `json:"uuid"`
+	DisplayName string `json:"displayName"`
+	DisplayIcon string `json:"displayIcon"`
+}
+
+// MapName resolves a map path as sent by riot (e.g. /Game/Maps/Ascent/Ascent) to its display name
+func MapName(mapUrl string) (string, error) {
+	var maps []Map
+	if err := Get(MapsUrl, &maps); err != nil {
+		return "", err
+	}
+
+	for _, m := range maps {
+		if m.MapUrl == mapUrl {
+			return m.DisplayName, nil
+		}
+	}
+
+	return mapUrl, nil
+}
+
+func AgentName(agentId string) (string, error) {
+	if agentId == "" {
+		return "", nil
+	}
+
+	agent := new(Agent)
+	if err := Get(fmt.Sprintf(AgentsUrl, agentId), agent); err != nil {
+		return "", err
+	}
+
+	return agent.DisplayName, nil
+}
+
+const (
+	WeaponsUrl      = BaseUrl + "/weapons"
+	LevelBordersUrl = BaseUrl + "/levelborders/%s"
+)
+
+type Weapon struct {
+	UUID            string       `json:"uuid"`
+	DisplayName     string       `json:"displayName"`
+	Category        string       `json:"category"`
+	DefaultSkinUuid string       `json:"defaultSkinUuid"`
+	DisplayIcon     string       `json:"displayIcon"`
+	Skins           []WeaponSkin `json:"skins"`
+}
+
+type WeaponSkin struct {
+	UUID        string       `json:"uuid"`
+	DisplayName string       `json:"displayName"`
+	DisplayIcon string       `json:"displayIcon"`
+	Chromas     []SkinChroma `json:"chromas"`
+	Levels      []SkinLevel  `json:"levels"`
+}
+
+type SkinChroma struct {
+	UUID        string `json:"uuid"`
+	DisplayName string `json:"displayName"`
+	DisplayIcon string `json:"displayIcon"`
+}
+
+type SkinLevel struct {
+	UUID        string `json:"uuid"`
+	DisplayName string `json:"displayName"`
+	DisplayIcon string `json:"displayIcon"`
+}
+
+type LevelBorder struct {
+	UUID                      string `json:"uuid"`
+	DisplayName               string `json:"displayName"`
+	StartingLevel             int    `json:"startingLevel"`
+	LevelNumberAppearance     string `json:"levelNumberAppearance"`
+	SmallPlayerCardAppearance string `json:"smallPlayerCardAppearance"`
+}
+
+func Weapons() (map[string]Weapon, error) {
+	var list []Weapon
+	if err := Get(WeaponsUrl, &list); err != nil {
+		return nil, err
+	}
+
+	weapons := map[string]Weapon{}
+	for _, weapon := range list {
+		weapons[weapon.UUID] = weapon
+	}
+
+	return weapons, nil
+}
+
+func (w Weapon) Skin(skinId string) *WeaponSkin {
+	for i := range w.Skins {
+		if w.Skins[i].UUID == skinId {
+			return &w.Skins[i]
+		}
+	}
+
+	return nil
+}
+
+func LevelBorderByID(id string) (*LevelBorder, error) {
+	border := new(LevelBorder)
+	if err := Get(fmt.Sprintf(LevelBordersUrl, id), border); err != nil {
+		return nil, err
+	}
+
+	return border, nil
+}
+
+const (
+	ContractsUrl = BaseUrl + "/contracts"
+
+	ContractRelationAgent  = "Agent"
+	ContractRelationSeason = "Season"
+	ContractRelationEvent  = "Event"
+)
+
+type ContractDefinition struct {
+	UUID        string `json:"uuid"`
+	DisplayName string `json:"displayName"`
+	DisplayIcon string `json:"displayIcon"`
+	Content     struct {
+		RelationType string            `json:"relationType"`
+		RelationUuid string            `json:"relationUuid"`
+		Chapters     []ContractChapter `json:"chapters"`
+	} `json:"content"`
+}
+
+type ContractChapter struct {
+	IsEpilogue bool `json:"isEpilogue"`
+	Levels     []struct {
+		Xp     int `json:"xp"`
+		Reward struct {
+			Type   string `json:"type"`
+			Uuid   string `json:"uuid"`
+			Amount int    `json:"amount"`
+		} `json:"reward"`
+	} `json:"levels"`
+}
+
+func Contracts() (map[string]ContractDefinition, error) {
+	var list []ContractDefinition
+	if err := Get(ContractsUrl, &list); err != nil {
+		return nil, err
+	}
+
+	contracts := map[string]ContractDefinition{}
+	for _, contract := range list {
+		contracts[contract.UUID] = contract
+	}
+
+	return contracts, nil
+}
+
+// LevelXp returns the xp needed for each level of the contract, epilogue levels excluded
+func (d ContractDefinition) LevelXp() []int {
+	xp := []int{}
+	for _, chapter := range d.Content.Chapters {
+		if chapter.IsEpilogue {
+			continue
+		}
+		for _, level := range chapter.Levels {
+			xp = append(xp, level.Xp)
+		}
+	}
+
+	return xp
+}
+
+const (
+	MissionsUrl   = BaseUrl + "/missions"
+	ObjectivesUrl = BaseUrl + "/objectives"
+)
+
+type MissionDefinition struct {
+	UUID               string `json:"uuid"`
+	DisplayName        string `json:"displayName"`
+	Title              string `json:"title"`
+	Type               string `json:"type"`
+	XpGrant            int    `json:"xpGrant"`
+	ProgressToComplete int    `json:"progressToComplete"`
+	Objectives         []struct {
+		ObjectiveUuid string `json:"objectiveUuid"`
+		Value         int    `json:"value"`
+	} `json:"objectives"`
+}
+
+type Objective struct {
+	UUID      string `json:"uuid"`
+	Directive string `json:"directive"`
+}
+
+func Missions() (map[string]MissionDefinition, error) {
+	var list []MissionDefinition
+	if err := Get(MissionsUrl, &list); err != nil {
+		return nil, err
+	}
+
+	missions := map[string]MissionDefinition{}
+	for _, mission := range list {
+		missions[mission.UUID] = mission
+	}
+
+	return missions, nil
+}
+
+func Objectives() (map[string]Objective, error) {
+	var list []Objective
+	if err := Get(ObjectivesUrl, &list); err != nil {
+		return nil, err
+	}
+
+	objectives := map[string]Objective{}
+	for _, objective := range list {
+		objectives[objective.UUID] = objective
+	}
+
+	return objectives, nil
+}
@@ -0,0 +1,101 @@
+package content
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+const (
+	BaseUrl         = "https://valorant-api.com/v1"
+	DefaultLanguage = "en-US"
+)
+
+// Languages supported by valorant-api.com
+var Languages = []string{
+	"ar-AE", "de-DE", "en-US", "es-ES", "es-MX", "fr-FR", "id-ID", "it-IT", "ja-JP",
+	"ko-KR", "pl-PL", "pt-BR", "ru-RU", "th-TH", "tr-TR", "vi-VN", "zh-CN", "zh-TW",
+}
+
+// Language is sent with every content request so names come back localized
+var Language = DefaultLanguage
+
+type apiResponse struct {
+	Status int             `json:"status"`
+	Error  string          `json:"error"`
+	Data   json.RawMessage `json:"data"`
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = map[string]json.RawMessage{}
+)
+
+func IsSupportedLanguage(language string) bool {
+	for _, supported := range Languages {
+		if supported == language {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get fetches a valorant-api.com url in the current Language and decodes its
+// "data" field into out. Content only changes with game patches, so responses
+// are cached for the lifetime of the process.
+func Get(rawUrl string, out any) error {
+	url, err := localizedUrl(rawUrl)
+	if err != nil {
+		return err
+	}
+
+	cacheMu.Lock()
+	data, ok := cache[url]
+	cacheMu.Unlock()
+
+	if !ok {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		defer res.Body.Close()
+
+		body := new(apiResponse)
+		if err = json.NewDecoder(res.Body).Decode(&body); err != nil {
+			return err
+		}
+
+		if body.Status != http.StatusOK {
+			return fmt.Errorf("valorant-api returned %d for %s: %s", body.Status, url, body.Error)
+		}
+
+		data = body.Data
+		cacheMu.Lock()
+		cache[url] = data
+		cacheMu.Unlock()
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+func localizedUrl(rawUrl string) (string, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("language", Language)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
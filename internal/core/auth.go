@@ -34,6 +34,10 @@ type Client struct {
 	HttpClient *http.Client
 	AuthData   *AuthSaveData
 	Region     string
+
+	// set when talking to a running Riot Client instead of logging in remotely
+	Lockfile        *Lockfile
+	LocalHttpClient *http.Client
 }
 
 type AuthSaveData struct {
@@ -187,6 +191,51 @@ func (c *Client) MultiFactorAuth(code string) error {
 	}
 }
 
+// Reauthorize gets fresh tokens with the session cookies of an earlier login, so long running
+// commands don't need the password or a multi factor code again
+func (c *Client) Reauthorize() error {
+	req, err := createNewRequest("GET", CookieReAuthUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	// riot answers with a redirect carrying the tokens, which must not be followed
+	noRedirects := &http.Client{
+		Transport: c.HttpClient.Transport,
+		Jar:       c.HttpClient.Jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	res, err := noRedirects.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	tokens, err := parseUriTokens(res.Header.Get("Location"))
+	if err != nil || tokens.AccessToken == "" {
+		return ErrorRiotAuthentication
+	}
+
+	c.AuthData.AuthTokens = *tokens
+	c.AuthData.SavedAt = time.Now()
+
+	return c.SetUserId()
+}
+
+// TokenExpiry returns when the access token stops working, riot hands out tokens for an hour
+func (c *Client) TokenExpiry() time.Time {
+	expiresIn := time.Duration(c.AuthData.AuthTokens.ExpiresIn) * time.Second
+	if expiresIn == 0 {
+		expiresIn = time.Hour
+	}
+
+	return c.AuthData.SavedAt.Add(expiresIn)
+}
+
 func (c *Client) SetUserId() error {
 	req, err := createNewRequest("GET", UserInfoUrl, nil)
 	if err != nil {
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"time"
+
+	"github.com/goamaan/valocli/internal/content"
 )
 
 type Version struct {
@@ -22,11 +24,6 @@ type ManifestData struct {
 	BuildDate         time.Time `json:"buildDate"`
 }
 
-type CompetitiveTierResponse struct {
-	Status int                           `json:"status"`
-	Data   []CompetitiveTierResponseData `json:"data"`
-}
-
 type CompetitiveTierResponseData struct {
 	Uuid            string `json:"uuid"`
 	AssetObjectName string `json:"assetObjectName"`
@@ -58,28 +55,34 @@ func GetClientVersion() (*string, error) {
 	return &versionBody.Data.RiotClientVersion, nil
 }
 
-func GetCompetitiveTiers() (map[int]string, error) {
-	req, err := http.NewRequest(http.MethodGet, "https://valorant-api.com/v1/competitivetiers", nil)
-	if err != nil {
+// GetCompetitiveTierSets returns the tier names of every tier set by its uuid, acts before
+// episode 5 use a set without ascendant where the same tier numbers mean other ranks
+func GetCompetitiveTierSets() (map[string]map[int]string, error) {
+	var tiersData []CompetitiveTierResponseData
+	if err := content.Get(content.BaseUrl+"/competitivetiers", &tiersData); err != nil {
 		return nil, err
 	}
 
-	res, err := http.DefaultClient.Do(req)
-
-	if err != nil {
-		return nil, err
+	sets := map[string]map[int]string{}
+	for _, set := range tiersData {
+		sets[set.Uuid] = map[int]string{}
+		for _, tier := range set.Tiers {
+			sets[set.Uuid][tier.Tier] = tier.TierName
+		}
 	}
 
-	defer res.Body.Close()
+	return sets, nil
+}
 
-	tiersBody := new(CompetitiveTierResponse)
-	if err = json.NewDecoder(res.Body).Decode(&tiersBody); err != nil {
+func GetCompetitiveTiers() (map[int]string, error) {
+	var tiersData []CompetitiveTierResponseData
+	if err := content.Get(content.BaseUrl+"/competitivetiers", &tiersData); err != nil {
 		return nil, err
 	}
 
 	tierMap := make(map[int]string)
 
-	for _, tier := range tiersBody.Data[len(tiersBody.Data)-1].Tiers {
+	for _, tier := range tiersData[len(tiersData)-1].Tiers {
 		tierMap[tier.Tier] = tier.TierName
 	}
 
@@ -6,10 +6,18 @@ var (
 	ErrorRiotAuthentication = errors.New("riot_authentication_error")
 	ErrorRiotMultifactor    = errors.New("riot_multifactor_error")
 	ErrorRiotRateLimit      = errors.New("riot_ratelimit_error")
+	ErrorRiotNotFound       = errors.New("riot_not_found_error")
 
 	ErrorRiotUnknownResponseType = errors.New("riot_unknown_response_type_error")
 	ErrorRiotUnknownErrorType    = errors.New("riot_unknown_error_type_error")
 
+	ErrorLockfileNotFound  = errors.New("lockfile_not_found_error")
+	ErrorLockfileMalformed = errors.New("lockfile_malformed_error")
+	ErrorLocalNotLoggedIn  = errors.New("local_not_logged_in_error")
+
+	ErrorRiotIdMalformed = errors.New("riot_id_malformed_error")
+	ErrorRiotIdNotFound  = errors.New("riot_id_not_found_error")
+
 	ResponseErrors = map[string]error{
 		"auth_failure": ErrorRiotAuthentication,
 		"rate_limited": ErrorRiotRateLimit,
@@ -0,0 +1,188 @@
+package core
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	LocalBaseUrl          = "%s://%s:%d"
+	LocalHost             = "127.0.0.1"
+	LocalEntitlementsPath = "/entitlements/v1/token"
+	LocalRegionLocalePath = "/riotclient/region-locale"
+	LocalUsername         = "riot"
+)
+
+// Lockfile is written by the Riot Client while it is running, as name:pid:port:password:protocol
+type Lockfile struct {
+	Name     string
+	Pid      int
+	Port     int
+	Password string
+	Protocol string
+	// Host is where the client api listens, LocalHost unless pointed at a stand-in
+	Host string
+}
+
+type LocalEntitlementsResponse struct {
+	AccessToken  string   `json:"accessToken"`
+	Entitlements []string `json:"entitlements"`
+	Issuer       string   `json:"issuer"`
+	Subject      string   `json:"subject"`
+	Token        string   `json:"token"`
+}
+
+type LocalRegionLocaleResponse struct {
+	Locale      string `json:"locale"`
+	Region      string `json:"region"`
+	WebLanguage string `json:"webLanguage"`
+	WebRegion   string `json:"webRegion"`
+}
+
+func DefaultLockfilePath() string {
+	localAppData := os.Getenv("LOCALAPPDATA")
+	if localAppData == "" {
+		return ""
+	}
+
+	return filepath.Join(localAppData, "Riot Games", "Riot Client", "Config", "lockfile")
+}
+
+func ReadLockfile(path string) (*Lockfile, error) {
+	if path == "" {
+		return nil, ErrorLockfileNotFound
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrorLockfileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseLockfile(string(data))
+}
+
+func ParseLockfile(data string) (*Lockfile, error) {
+	parts := strings.Split(strings.TrimSpace(data), ":")
+	if len(parts) != 5 {
+		return nil, ErrorLockfileMalformed
+	}
+
+	pid, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, ErrorLockfileMalformed
+	}
+
+	port, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, ErrorLockfileMalformed
+	}
+
+	return &Lockfile{Name: parts[0], Pid: pid, Port: port, Password: parts[3], Protocol: parts[4], Host: LocalHost}, nil
+}
+
+func (l *Lockfile) BaseUrl() string {
+	host := l.Host
+	if host == "" {
+		host = LocalHost
+	}
+
+	return fmt.Sprintf(LocalBaseUrl, l.Protocol, host, l.Port)
+}
+
+// UseLockfile points the client at the local Riot Client API described by the lockfile
+func (c *Client) UseLockfile(lockfile *Lockfile) {
+	// the riot client serves a self signed certificate on 127.0.0.1
+	c.UseLockfileWith(lockfile, &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	})
+}
+
+// UseLockfileWith is UseLockfile with the http client used to reach the client api
+func (c *Client) UseLockfileWith(lockfile *Lockfile, httpClient *http.Client) {
+	c.Lockfile = lockfile
+	c.LocalHttpClient = httpClient
+}
+
+func (c *Client) IsLocal() bool {
+	return c.Lockfile != nil
+}
+
+func (c *Client) RequestLocal(method, path string, body io.Reader) (*http.Request, error) {
+	if c.Lockfile == nil {
+		return nil, ErrorLockfileNotFound
+	}
+
+	req, err := http.NewRequest(method, c.Lockfile.BaseUrl()+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(LocalUsername, c.Lockfile.Password)
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+// GetLocal sends a GET to the local Riot Client API and decodes the json response into out
+func (c *Client) GetLocal(path string, out any) error {
+	req, err := c.RequestLocal("GET", path, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.LocalHttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("local riot client returned %s for %s", res.Status, path)
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// AuthorizeLocal takes the access and entitlement tokens from the running Riot Client,
+// so no password is needed. It can be called again whenever the tokens expire.
+func (c *Client) AuthorizeLocal() error {
+	body := new(LocalEntitlementsResponse)
+	if err := c.GetLocal(LocalEntitlementsPath, body); err != nil {
+		return err
+	}
+
+	if body.AccessToken == "" || body.Token == "" {
+		return ErrorLocalNotLoggedIn
+	}
+
+	c.AuthData.AuthTokens = UriTokens{AccessToken: body.AccessToken}
+	c.AuthData.EntitlementToken = body.Token
+	c.AuthData.UserId = body.Subject
+	c.AuthData.SavedAt = time.Now()
+
+	return nil
+}
+
+// LocalRegion returns the region the running Riot Client is logged into, in the same
+// form as the region saved in the config (na, latam, br, eu, ap or kr)
+func (c *Client) LocalRegion() (string, error) {
+	body := new(LocalRegionLocaleResponse)
+	if err := c.GetLocal(LocalRegionLocalePath, body); err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(body.Region), nil
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const standInPassword = "s3cret"
+
+// newLocalStandIn serves handler like the Riot Client's local api and returns a client
+// using it through a lockfile
+func newLocalStandIn(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != LocalUsername || password != standInPassword {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockfile, err := ParseLockfile(fmt.Sprintf("Riot Client:4242:%s:%s:https", u.Port(), standInPassword))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockfile.Host = u.Hostname()
+
+	c := New(nil)
+	c.UseLockfileWith(lockfile, srv.Client())
+	return c
+}
+
+func TestParseLockfile(t *testing.T) {
+	lockfile, err := ParseLockfile("Riot Client:1234:56789:password:https\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Lockfile{Name: "Riot Client", Pid: 1234, Port: 56789, Password: "password", Protocol: "https", Host: LocalHost}
+	if *lockfile != want {
+		t.Errorf("got %+v, want %+v", *lockfile, want)
+	}
+	if got := lockfile.BaseUrl(); got != "https://127.0.0.1:56789" {
+		t.Errorf("BaseUrl() = %s", got)
+	}
+
+	for _, malformed := range []string{"", "Riot Client:1234:56789:pass:word:https", "Riot Client:1234:56789:password", "Riot Client:pid:56789:password:https", "Riot Client:1234:port:password:https"} {
+		if _, err := ParseLockfile(malformed); !errors.Is(err, ErrorLockfileMalformed) {
+			t.Errorf("ParseLockfile(%q) = %v, want ErrorLockfileMalformed", malformed, err)
+		}
+	}
+}
+
+func TestReadLockfile(t *testing.T) {
+	if _, err := ReadLockfile(""); !errors.Is(err, ErrorLockfileNotFound) {
+		t.Errorf("empty path: got %v, want ErrorLockfileNotFound", err)
+	}
+
+	dir := t.TempDir()
+	if _, err := ReadLockfile(filepath.Join(dir, "lockfile")); !errors.Is(err, ErrorLockfileNotFound) {
+		t.Errorf("missing file: got %v, want ErrorLockfileNotFound", err)
+	}
+
+	path := filepath.Join(dir, "lockfile")
+	if err := os.WriteFile(path, []byte("Riot Client:1:2:p:https"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lockfile, err := ReadLockfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lockfile.Port != 2 || lockfile.Password != "p" {
+		t.Errorf("got %+v", lockfile)
+	}
+}
+
+func TestAuthorizeLocal(t *testing.T) {
+	c := newLocalStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != LocalEntitlementsPath {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"accessToken":"access","entitlements":[],"issuer":"riot","subject":"puuid-1","token":"entitlement"}`)
+	})
+
+	if err := c.AuthorizeLocal(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !c.IsLocal() {
+		t.Error("IsLocal() = false")
+	}
+	if c.AuthData.AuthTokens.AccessToken != "access" || c.AuthData.EntitlementToken != "entitlement" || c.AuthData.UserId != "puuid-1" {
+		t.Errorf("got auth data %+v", c.AuthData)
+	}
+	if c.AuthData.SavedAt.IsZero() {
+		t.Error("SavedAt not set")
+	}
+}
+
+func TestAuthorizeLocalNotLoggedIn(t *testing.T) {
+	c := newLocalStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"accessToken":"","entitlements":[],"issuer":"","subject":"","token":""}`)
+	})
+
+	if err := c.AuthorizeLocal(); !errors.Is(err, ErrorLocalNotLoggedIn) {
+		t.Errorf("got %v, want ErrorLocalNotLoggedIn", err)
+	}
+}
+
+func TestAuthorizeLocalWrongPassword(t *testing.T) {
+	c := newLocalStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached the handler with a wrong password")
+	})
+	c.Lockfile.Password = "wrong"
+
+	if err := c.AuthorizeLocal(); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestLocalRegion(t *testing.T) {
+	c := newLocalStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != LocalRegionLocalePath {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"locale":"en_GB","region":"EU","webLanguage":"en","webRegion":"eu"}`)
+	})
+
+	region, err := c.LocalRegion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if region != "eu" {
+		t.Errorf("LocalRegion() = %q, want eu", region)
+	}
+}
+
+func TestRequestLocalWithoutLockfile(t *testing.T) {
+	c := New(nil)
+	if _, err := c.RequestLocal("GET", LocalEntitlementsPath, nil); !errors.Is(err, ErrorLockfileNotFound) {
+		t.Errorf("got %v, want ErrorLockfileNotFound", err)
+	}
+}
+
+func TestGetLocalStatus(t *testing.T) {
+	c := newLocalStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	err := c.GetLocal(LocalRegionLocalePath, new(LocalRegionLocaleResponse))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if want := strconv.Itoa(http.StatusServiceUnavailable); !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q doesn't mention the status", err)
+	}
+}
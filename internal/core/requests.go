@@ -0,0 +1,92 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+const (
+	ClientPlatform = "ew0KCSJwbGF0Zm9ybVR5cGUiOiAiUEMiLA0KCSJwbGF0Zm9ybU9TIjogIldpbmRvd3MiLA0KCSJwbGF0Zm9ybU9TVmVyc2lvbiI6ICIxMC4wLjE5MDQyLjEuMjU2LjY0Yml0IiwNCgkicGxhdGZvcm1DaGlwc2V0IjogIlVua25vd24iDQp9"
+)
+
+var (
+	clientVersionMu sync.Mutex
+	clientVersion   string
+)
+
+// Shard returns the shard that serves the client's region, latam and br players are served by na
+func (c *Client) Shard() string {
+	switch c.Region {
+	case "latam", "br":
+		return "na"
+	default:
+		return c.Region
+	}
+}
+
+// RequestWithClientInfo creates an authenticated request that also carries the client
+// platform and version headers, which the glz and most pd endpoints require
+func (c *Client) RequestWithClientInfo(method, url string, body io.Reader) (*http.Request, error) {
+	req, err := c.RequestWithAuth(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := cachedClientVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-Riot-ClientPlatform", ClientPlatform)
+	req.Header.Set("X-Riot-ClientVersion", version)
+
+	return req, nil
+}
+
+// DoJSON sends the request and decodes a successful json response into out, out may be nil
+func (c *Client) DoJSON(req *http.Request, out any) error {
+	res, err := c.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return ErrorRiotNotFound
+	case res.StatusCode == http.StatusTooManyRequests:
+		return ErrorRiotRateLimit
+	case res.StatusCode == http.StatusUnauthorized:
+		return ErrorRiotAuthentication
+	case res.StatusCode >= 300:
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("riot api returned %s for %s %s: %s", res.Status, req.Method, req.URL.Path, body)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+func cachedClientVersion() (string, error) {
+	clientVersionMu.Lock()
+	defer clientVersionMu.Unlock()
+
+	if clientVersion != "" {
+		return clientVersion, nil
+	}
+
+	version, err := GetClientVersion()
+	if err != nil {
+		return "", err
+	}
+
+	clientVersion = *version
+	return clientVersion, nil
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// refresh tokens this long before riot expires them
+const RefreshMargin = 5 * time.Minute
+
+// Session shares a client between goroutines that keep running past riot's one hour token
+// expiry, like the server and the full screen interface
+type Session struct {
+	client *Client
+	// refresh gets the client new tokens, it must not wait for input as requests are
+	// blocked while it runs
+	refresh func() error
+
+	// held for writing while tokens are refreshed
+	mu sync.RWMutex
+
+	// copied from the client after every refresh, so they can be shown without waiting for one
+	statusMu    sync.Mutex
+	tokenExpiry time.Time
+	region      string
+}
+
+func NewSession(c *Client, refresh func() error) *Session {
+	s := &Session{client: c, refresh: refresh}
+	s.updateStatus()
+	return s
+}
+
+// Do runs fn with valid tokens, refreshing them and trying again once if riot rejects them
+func (s *Session) Do(fn func(c *Client) (any, error)) (any, error) {
+	s.mu.RLock()
+	value, err := fn(s.client)
+	s.mu.RUnlock()
+
+	if !errors.Is(err, ErrorRiotAuthentication) {
+		return value, err
+	}
+
+	if err = s.Refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(s.client)
+}
+
+// Refresh gets new tokens, waiting for requests that are using the current ones
+func (s *Session) Refresh() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.refresh == nil {
+		return ErrorRiotAuthentication
+	}
+
+	defer s.updateStatus()
+	return s.refresh()
+}
+
+// TokenExpiry returns when the current tokens expire, it doesn't wait for a refresh in progress
+func (s *Session) TokenExpiry() time.Time {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	return s.tokenExpiry
+}
+
+// Region returns the region of the client, it doesn't wait for a refresh in progress
+func (s *Session) Region() string {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	return s.region
+}
+
+// updateStatus must be called while nothing changes the client, before the session is shared
+// or with mu held
+func (s *Session) updateStatus() {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.tokenExpiry = s.client.TokenExpiry()
+	s.region = s.client.Region
+}
+
+// RefreshIfExpiring gets new tokens when the current ones expire within RefreshMargin
+func (s *Session) RefreshIfExpiring() error {
+	if time.Until(s.TokenExpiry()) > RefreshMargin {
+		return nil
+	}
+
+	return s.Refresh()
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSessionDoRefreshesOnce(t *testing.T) {
+	c := New(nil)
+	c.AuthData.AuthTokens.AccessToken = "expired"
+
+	refreshes := 0
+	session := NewSession(c, func() error {
+		refreshes++
+		c.AuthData.AuthTokens.AccessToken = "fresh"
+		return nil
+	})
+
+	calls := 0
+	value, err := session.Do(func(c *Client) (any, error) {
+		calls++
+		if c.AuthData.AuthTokens.AccessToken != "fresh" {
+			return nil, ErrorRiotAuthentication
+		}
+		return "ok", nil
+	})
+	if err != nil || value != "ok" {
+		t.Fatalf("got %v, %v", value, err)
+	}
+	if calls != 2 || refreshes != 1 {
+		t.Errorf("fn ran %d times with %d refreshes, want 2 and 1", calls, refreshes)
+	}
+
+	_, err = session.Do(func(c *Client) (any, error) { return nil, ErrorRiotAuthentication })
+	if !errors.Is(err, ErrorRiotAuthentication) || refreshes != 2 {
+		t.Errorf("still rejected after a refresh: %v with %d refreshes, want the error and no third refresh", err, refreshes)
+	}
+}
+
+func TestSessionDoOtherErrors(t *testing.T) {
+	session := NewSession(New(nil), func() error {
+		t.Error("refreshed on an error that isn't about the tokens")
+		return nil
+	})
+
+	if _, err := session.Do(func(c *Client) (any, error) { return nil, ErrorRiotRateLimit }); !errors.Is(err, ErrorRiotRateLimit) {
+		t.Errorf("got %v, want ErrorRiotRateLimit", err)
+	}
+}
+
+func TestSessionWithoutRefresh(t *testing.T) {
+	session := NewSession(New(nil), nil)
+
+	if err := session.Refresh(); !errors.Is(err, ErrorRiotAuthentication) {
+		t.Errorf("got %v, want ErrorRiotAuthentication", err)
+	}
+}
+
+func TestSessionRefreshIfExpiring(t *testing.T) {
+	c := New(nil)
+	c.AuthData.AuthTokens.ExpiresIn = 3600
+	c.AuthData.SavedAt = time.Now().Add(-58 * time.Minute)
+
+	refreshes := 0
+	session := NewSession(c, func() error {
+		refreshes++
+		c.AuthData.SavedAt = time.Now()
+		return nil
+	})
+
+	if err := session.RefreshIfExpiring(); err != nil || refreshes != 1 {
+		t.Errorf("expiring tokens not refreshed: %v, %d refreshes", err, refreshes)
+	}
+
+	if err := session.RefreshIfExpiring(); err != nil || refreshes != 1 {
+		t.Errorf("fresh tokens refreshed: %v, %d refreshes", err, refreshes)
+	}
+}
+
+func TestSessionStatusDuringRefresh(t *testing.T) {
+	c := New(nil)
+	c.Region = "eu"
+	c.AuthData.SavedAt = time.Now().Add(-50 * time.Minute)
+
+	refreshing, finish := make(chan struct{}), make(chan struct{})
+	session := NewSession(c, func() error {
+		close(refreshing)
+		<-finish
+		c.AuthData.SavedAt = time.Now()
+		return nil
+	})
+	before := session.TokenExpiry()
+
+	done := make(chan error)
+	go func() { done <- session.Refresh() }()
+	<-refreshing
+
+	// reading the status must not wait for the refresh
+	if session.Region() != "eu" || !session.TokenExpiry().Equal(before) {
+		t.Errorf("status during refresh: %s %s", session.Region(), session.TokenExpiry())
+	}
+
+	close(finish)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !session.TokenExpiry().After(before) {
+		t.Errorf("expiry %s not updated after the refresh", session.TokenExpiry())
+	}
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"time"
+)
+
+type Kind string
+
+const (
+	StoreRotated      Kind = "store_rotated"
+	NightMarketOpened Kind = "night_market_opened"
+	WishlistItem      Kind = "wishlist_item"
+	RankChanged       Kind = "rank_changed"
+)
+
+var Kinds = []Kind{StoreRotated, NightMarketOpened, WishlistItem, RankChanged}
+
+// Event is something worth telling the player about, sent to every sink as is
+type Event struct {
+	Kind        Kind
+	Title       string
+	Description string
+	Time        time.Time
+	Items       []Item      `json:",omitempty"`
+	Rank        *RankChange `json:",omitempty"`
+}
+
+// Item is a store offer an event is about
+type Item struct {
+	Name        string
+	Price       string
+	DisplayIcon string
+}
+
+// RankChange is the competitive update of the last match
+type RankChange struct {
+	MatchID        string
+	TierBefore     int
+	TierAfter      int
+	TierNameBefore string
+	TierNameAfter  string
+	RRBefore       int
+	RRAfter        int
+	RREarned       int
+	Movement       string
+}
+
+func IsKind(kind string) bool {
+	for _, k := range Kinds {
+		if string(k) == kind {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,280 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+const (
+	SinkWebhook = "webhook"
+	SinkDiscord = "discord"
+	SinkSlack   = "slack"
+	SinkCommand = "command"
+
+	sinkTimeout = 10 * time.Second
+	// discord rejects messages with more than 10 embeds
+	maxDiscordEmbeds = 10
+)
+
+// Sink delivers events somewhere outside valocli
+type Sink interface {
+	Name() string
+	Send(event Event) error
+}
+
+// SinkConfig is a sink as written in the config file
+type SinkConfig struct {
+	Type    string `json:"type"`
+	URL     string `json:"url,omitempty"`
+	Command string `json:"command,omitempty"`
+	// only these kinds of events are sent, all of them when empty
+	Events []Kind `json:"events,omitempty"`
+}
+
+// NewSink creates the sink a config describes
+func NewSink(config SinkConfig) (Sink, error) {
+	for _, kind := range config.Events {
+		if !IsKind(string(kind)) {
+			return nil, fmt.Errorf("unknown event %q, events are %v", kind, Kinds)
+		}
+	}
+
+	var sink Sink
+	switch config.Type {
+	case SinkWebhook:
+		sink = &WebhookSink{URL: config.URL}
+	case SinkDiscord:
+		sink = &DiscordSink{URL: config.URL}
+	case SinkSlack:
+		sink = &SlackSink{URL: config.URL}
+	case SinkCommand:
+		if config.Command == "" {
+			return nil, fmt.Errorf("command sink needs a command")
+		}
+		return filtered(&CommandSink{Command: config.Command}, config.Events), nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q, types are %s, %s, %s and %s", config.Type, SinkWebhook, SinkDiscord, SinkSlack, SinkCommand)
+	}
+
+	if config.URL == "" {
+		return nil, fmt.Errorf("%s sink needs a url", config.Type)
+	}
+
+	return filtered(sink, config.Events), nil
+}
+
+type filteredSink struct {
+	Sink
+	kinds []Kind
+}
+
+func filtered(sink Sink, kinds []Kind) Sink {
+	if len(kinds) == 0 {
+		return sink
+	}
+
+	return &filteredSink{Sink: sink, kinds: kinds}
+}
+
+func (s *filteredSink) Send(event Event) error {
+	for _, kind := range s.kinds {
+		if kind == event.Kind {
+			return s.Sink.Send(event)
+		}
+	}
+
+	return nil
+}
+
+// WebhookSink posts the event as json
+type WebhookSink struct {
+	URL string
+}
+
+func (s *WebhookSink) Name() string {
+	return SinkWebhook + " " + s.URL
+}
+
+func (s *WebhookSink) Send(event Event) error {
+	return postJSON(s.URL, event)
+}
+
+// DiscordSink posts the event as discord embeds, one per item with its icon
+type DiscordSink struct {
+	URL string
+}
+
+type discordMessage struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+	Thumbnail   *discordImage  `json:"thumbnail,omitempty"`
+	Fields      []discordField `json:"fields,omitempty"`
+}
+
+type discordImage struct {
+	URL string `json:"url"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+var discordColors = map[Kind]int{
+	StoreRotated:      0xff4655,
+	NightMarketOpened: 0x7b2cbf,
+	WishlistItem:      0xf5c542,
+	RankChanged:       0x2ec4b6,
+}
+
+func (s *DiscordSink) Name() string {
+	return SinkDiscord
+}
+
+func (s *DiscordSink) Send(event Event) error {
+	color := discordColors[event.Kind]
+	header := discordEmbed{
+		Title:       event.Title,
+		Description: event.Description,
+		Color:       color,
+		Timestamp:   event.Time.Format(time.RFC3339),
+	}
+	if event.Rank != nil {
+		header.Fields = []discordField{
+			{Name: "Before", Value: fmt.Sprintf("%s %d RR", event.Rank.TierNameBefore, event.Rank.RRBefore), Inline: true},
+			{Name: "After", Value: fmt.Sprintf("%s %d RR", event.Rank.TierNameAfter, event.Rank.RRAfter), Inline: true},
+		}
+	}
+
+	message := discordMessage{Embeds: []discordEmbed{header}}
+	for _, item := range event.Items {
+		if len(message.Embeds) == maxDiscordEmbeds {
+			break
+		}
+
+		embed := discordEmbed{Title: item.Name, Description: item.Price, Color: color}
+		if item.DisplayIcon != "" {
+			embed.Thumbnail = &discordImage{URL: item.DisplayIcon}
+		}
+		message.Embeds = append(message.Embeds, embed)
+	}
+
+	return postJSON(s.URL, message)
+}
+
+// SlackSink posts the event to a slack incoming webhook, with the item icons next to their names
+type SlackSink struct {
+	URL string
+}
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type      string      `json:"type"`
+	Text      *slackText  `json:"text,omitempty"`
+	Accessory *slackImage `json:"accessory,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackImage struct {
+	Type     string `json:"type"`
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+func (s *SlackSink) Name() string {
+	return SinkSlack
+}
+
+func (s *SlackSink) Send(event Event) error {
+	message := slackMessage{
+		Text: event.Title,
+		Blocks: []slackBlock{
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", event.Title, event.Description)}},
+		},
+	}
+
+	for _, item := range event.Items {
+		block := slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", item.Name, item.Price)}}
+		if item.DisplayIcon != "" {
+			block.Accessory = &slackImage{Type: "image", ImageURL: item.DisplayIcon, AltText: item.Name}
+		}
+		message.Blocks = append(message.Blocks, block)
+	}
+
+	return postJSON(s.URL, message)
+}
+
+// CommandSink runs a shell command with the event as json on stdin, and its kind and title in
+// VALOCLI_EVENT and VALOCLI_TITLE
+type CommandSink struct {
+	Command string
+}
+
+func (s *CommandSink) Name() string {
+	return SinkCommand + " " + s.Command
+}
+
+func (s *CommandSink) Send(event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", s.Command)
+	} else {
+		cmd = exec.Command("sh", "-c", s.Command)
+	}
+
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "VALOCLI_EVENT="+string(event.Kind), "VALOCLI_TITLE="+event.Title)
+
+	return cmd.Run()
+}
+
+var sinkClient = &http.Client{Timeout: sinkTimeout}
+
+func postJSON(url string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	res, err := sinkClient.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("%s: %s", res.Status, bytes.TrimSpace(message))
+	}
+
+	return nil
+}
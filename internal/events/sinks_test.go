@@ -0,0 +1,201 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// receiver records every body posted to it and answers with status
+func receiver(t *testing.T, status int) (*httptest.Server, <-chan []byte) {
+	t.Helper()
+
+	bodies := make(chan []byte, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+
+		w.WriteHeader(status)
+		if status >= 300 {
+			fmt.Fprint(w, "  invalid webhook token\n")
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, bodies
+}
+
+func received[T any](t *testing.T, bodies <-chan []byte) T {
+	t.Helper()
+
+	var out T
+	select {
+	case body := <-bodies:
+		if err := json.Unmarshal(body, &out); err != nil {
+			t.Fatalf("posted invalid json %s: %s", body, err)
+		}
+	default:
+		t.Fatal("nothing was posted")
+	}
+
+	return out
+}
+
+func storeEvent(items int) Event {
+	event := Event{
+		Kind:        StoreRotated,
+		Title:       "Daily store rotated",
+		Description: "New offers for the next 23h 59m",
+		Time:        time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for i := 0; i < items; i++ {
+		item := Item{Name: fmt.Sprintf("Skin %d", i), Price: "1775 VP"}
+		if i%2 == 0 {
+			item.DisplayIcon = fmt.Sprintf("https://media.valorant-api.com/%d.png", i)
+		}
+		event.Items = append(event.Items, item)
+	}
+
+	return event
+}
+
+func TestWebhookSink(t *testing.T) {
+	srv, bodies := receiver(t, http.StatusNoContent)
+
+	if err := (&WebhookSink{URL: srv.URL}).Send(storeEvent(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	event := received[Event](t, bodies)
+	if event.Kind != StoreRotated || event.Title != "Daily store rotated" || len(event.Items) != 2 || event.Items[0].DisplayIcon == "" {
+		t.Errorf("posted %+v", event)
+	}
+	if event.Rank != nil {
+		t.Errorf("store event posted with a rank: %+v", event.Rank)
+	}
+}
+
+func TestDiscordSink(t *testing.T) {
+	srv, bodies := receiver(t, http.StatusNoContent)
+
+	if err := (&DiscordSink{URL: srv.URL}).Send(storeEvent(3)); err != nil {
+		t.Fatal(err)
+	}
+
+	message := received[discordMessage](t, bodies)
+	if len(message.Embeds) != 4 {
+		t.Fatalf("got %d embeds, want a header and one per item", len(message.Embeds))
+	}
+
+	header := message.Embeds[0]
+	if header.Title != "Daily store rotated" || header.Color != discordColors[StoreRotated] || header.Timestamp != "2024-05-01T00:00:00Z" {
+		t.Errorf("header embed %+v", header)
+	}
+	if item := message.Embeds[1]; item.Title != "Skin 0" || item.Description != "1775 VP" ||
+		item.Thumbnail == nil || item.Thumbnail.URL != "https://media.valorant-api.com/0.png" {
+		t.Errorf("item embed %+v", item)
+	}
+	if item := message.Embeds[2]; item.Thumbnail != nil {
+		t.Errorf("item without an icon got thumbnail %+v", item.Thumbnail)
+	}
+}
+
+func TestDiscordSinkEmbedCap(t *testing.T) {
+	srv, bodies := receiver(t, http.StatusNoContent)
+
+	if err := (&DiscordSink{URL: srv.URL}).Send(storeEvent(15)); err != nil {
+		t.Fatal(err)
+	}
+
+	message := received[discordMessage](t, bodies)
+	if len(message.Embeds) != maxDiscordEmbeds {
+		t.Errorf("got %d embeds, discord takes at most %d", len(message.Embeds), maxDiscordEmbeds)
+	}
+}
+
+func TestDiscordSinkRank(t *testing.T) {
+	srv, bodies := receiver(t, http.StatusNoContent)
+
+	event := Event{Kind: RankChanged, Title: "Ranked up to Gold 1", Rank: &RankChange{
+		TierNameBefore: "Silver 3", RRBefore: 90, TierNameAfter: "Gold 1", RRAfter: 12,
+	}}
+	if err := (&DiscordSink{URL: srv.URL}).Send(event); err != nil {
+		t.Fatal(err)
+	}
+
+	message := received[discordMessage](t, bodies)
+	if len(message.Embeds) != 1 || len(message.Embeds[0].Fields) != 2 {
+		t.Fatalf("got %+v", message.Embeds)
+	}
+	if before, after := message.Embeds[0].Fields[0], message.Embeds[0].Fields[1]; before.Value != "Silver 3 90 RR" || after.Value != "Gold 1 12 RR" {
+		t.Errorf("rank fields %+v and %+v", before, after)
+	}
+}
+
+func TestSlackSink(t *testing.T) {
+	srv, bodies := receiver(t, http.StatusOK)
+
+	if err := (&SlackSink{URL: srv.URL}).Send(storeEvent(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	message := received[slackMessage](t, bodies)
+	if message.Text != "Daily store rotated" || len(message.Blocks) != 3 {
+		t.Fatalf("posted %+v", message)
+	}
+	if header := message.Blocks[0]; header.Text == nil || header.Text.Text != "*Daily store rotated*\nNew offers for the next 23h 59m" || header.Accessory != nil {
+		t.Errorf("header block %+v", header)
+	}
+
+	withIcon := message.Blocks[1]
+	if withIcon.Accessory == nil || withIcon.Accessory.Type != "image" ||
+		withIcon.Accessory.ImageURL != "https://media.valorant-api.com/0.png" || withIcon.Accessory.AltText != "Skin 0" {
+		t.Errorf("item block %+v", withIcon.Accessory)
+	}
+	if withoutIcon := message.Blocks[2]; withoutIcon.Accessory != nil {
+		t.Errorf("item without an icon got accessory %+v", withoutIcon.Accessory)
+	}
+}
+
+func TestSinkErrorStatus(t *testing.T) {
+	srv, _ := receiver(t, http.StatusUnauthorized)
+
+	for _, sink := range []Sink{&WebhookSink{URL: srv.URL}, &DiscordSink{URL: srv.URL}, &SlackSink{URL: srv.URL}} {
+		err := sink.Send(storeEvent(1))
+		if err == nil {
+			t.Errorf("%s: no error for a 401", sink.Name())
+			continue
+		}
+		if !strings.Contains(err.Error(), "401 Unauthorized: invalid webhook token") {
+			t.Errorf("%s: error %q doesn't say what the server answered", sink.Name(), err)
+		}
+	}
+}
+
+func TestDispatchKeepsGoing(t *testing.T) {
+	failing, _ := receiver(t, http.StatusInternalServerError)
+	working, bodies := receiver(t, http.StatusNoContent)
+
+	w := &Watcher{Sinks: []Sink{
+		&WebhookSink{URL: failing.URL},
+		&WebhookSink{URL: working.URL},
+		filtered(&WebhookSink{URL: working.URL}, []Kind{RankChanged}),
+	}}
+
+	err := w.Dispatch([]Event{storeEvent(1)})
+	if err == nil || !strings.Contains(err.Error(), failing.URL) {
+		t.Errorf("got error %v, want one naming the failing sink", err)
+	}
+	if len(bodies) != 1 {
+		t.Errorf("working sinks got %d posts, want 1 (the filtered one skips store events)", len(bodies))
+	}
+}
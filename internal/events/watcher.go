@@ -0,0 +1,270 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/player"
+	"github.com/goamaan/valocli/internal/store"
+)
+
+// State is what the last poll saw, saved between runs so a restart doesn't fire everything again
+type State struct {
+	DailyStore      []string
+	NightMarketOpen bool
+	LastRankMatchID string
+	Initialized     bool
+}
+
+// Watcher polls the store and competitive updates and turns the differences into events
+type Watcher struct {
+	StatePath string
+	Wishlist  []string
+	Sinks     []Sink
+
+	state State
+	// what the last poll saw, remembered once its events are delivered
+	polled *State
+
+	// where the store, competitive updates and rank names come from, riot and the content api
+	// unless a test stands in for them
+	fetchStore func(c *core.Client) (*store.StoreCliTable, error)
+	fetchMMR   func(c *core.Client, puuid string) (*player.PlayerMMRResponse, error)
+	fetchTiers func() (map[int]string, error)
+}
+
+// NewWatcher loads the state of the previous run from statePath, if there is one. Wishlist
+// entries are trimmed and blank ones dropped, as a blank one would match every item
+func NewWatcher(statePath string, wishlist []string, sinks []Sink) (*Watcher, error) {
+	wishes := []string{}
+	for _, wish := range wishlist {
+		if wish = strings.TrimSpace(wish); wish != "" {
+			wishes = append(wishes, wish)
+		}
+	}
+
+	w := &Watcher{
+		StatePath:  statePath,
+		Wishlist:   wishes,
+		Sinks:      sinks,
+		fetchStore: store.GetStoreTable,
+		fetchMMR:   player.FetchPlayerMMR,
+		fetchTiers: core.GetCompetitiveTiers,
+	}
+
+	data, err := os.ReadFile(statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return w, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, &w.state); err != nil {
+		return nil, fmt.Errorf("could not read event state %s: %w", statePath, err)
+	}
+
+	return w, nil
+}
+
+// Poll compares the store and the latest competitive update with the previous poll. The
+// first poll only remembers what it saw, apart from wishlisted items which are always news.
+// What it saw only replaces the previous poll once Dispatch delivered its events
+func (w *Watcher) Poll(c *core.Client) ([]Event, error) {
+	table, err := w.fetchStore(c)
+	if err != nil {
+		return nil, err
+	}
+
+	mmr, err := w.fetchMMR(c, c.AuthData.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	events := []Event{}
+	first := !w.state.Initialized
+
+	daily := []string{}
+	dailyItems := []Item{}
+	for _, item := range table.DailyStore {
+		daily = append(daily, item.Item)
+		dailyItems = append(dailyItems, Item{Name: item.Item, Price: table.Currencies.FormatCost(item.Cost), DisplayIcon: item.DisplayIcon})
+	}
+
+	rotated := !sameItems(daily, w.state.DailyStore)
+	if rotated && !first {
+		events = append(events, Event{
+			Kind:        StoreRotated,
+			Title:       "Daily store rotated",
+			Description: fmt.Sprintf("New offers for the next %s", store.FormatCountdown(table.DailyStoreRemaining)),
+			Time:        now,
+			Items:       dailyItems,
+		})
+	}
+
+	nightMarket := []Item{}
+	for _, item := range table.NightMarket {
+		nightMarket = append(nightMarket, Item{
+			Name:        item.Item,
+			Price:       fmt.Sprintf("%s (-%d%%)", table.Currencies.FormatCost(item.DiscountCost), item.DiscountPercent),
+			DisplayIcon: item.DisplayIcon,
+		})
+	}
+
+	opened := len(nightMarket) > 0 && !w.state.NightMarketOpen
+	if opened && !first {
+		events = append(events, Event{
+			Kind:        NightMarketOpened,
+			Title:       "Night market is open",
+			Description: fmt.Sprintf("Open for %s", store.FormatCountdown(table.NightMarketRemaining)),
+			Time:        now,
+			Items:       nightMarket,
+		})
+	}
+
+	wished := []Item{}
+	if rotated || first {
+		wished = append(wished, w.wishlisted(dailyItems)...)
+	}
+	if opened {
+		wished = append(wished, w.wishlisted(nightMarket)...)
+	}
+	if len(wished) > 0 {
+		names := []string{}
+		for _, item := range wished {
+			names = append(names, item.Name)
+		}
+
+		events = append(events, Event{
+			Kind:        WishlistItem,
+			Title:       "Wishlisted item in your store",
+			Description: strings.Join(names, ", "),
+			Time:        now,
+			Items:       wished,
+		})
+	}
+
+	update := mmr.LatestCompetitiveUpdate
+	if update.MatchID != "" && update.MatchID != w.state.LastRankMatchID && !first {
+		tierMap, err := w.fetchTiers()
+		if err != nil {
+			return nil, err
+		}
+		rank := rankChange(update, tierMap)
+
+		events = append(events, Event{
+			Kind:        RankChanged,
+			Title:       rankTitle(rank),
+			Description: fmt.Sprintf("%s %d RR → %s %d RR (%+d)", rank.TierNameBefore, rank.RRBefore, rank.TierNameAfter, rank.RRAfter, rank.RREarned),
+			Time:        now,
+			Rank:        rank,
+		})
+	}
+
+	w.polled = &State{
+		DailyStore:      daily,
+		NightMarketOpen: len(nightMarket) > 0,
+		LastRankMatchID: update.MatchID,
+		Initialized:     true,
+	}
+
+	return events, nil
+}
+
+// Dispatch sends every event to every sink, a failing sink doesn't stop the others. When
+// every sink succeeded the last poll is saved, otherwise the next poll finds the same events
+// again, so a sink that did get them may get them twice
+func (w *Watcher) Dispatch(events []Event) error {
+	polled := w.polled
+	w.polled = nil
+
+	var errs []string
+	for _, event := range events {
+		for _, sink := range w.Sinks {
+			if err := sink.Send(event); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %s", sink.Name(), err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("could not send events: %s", strings.Join(errs, "; "))
+	}
+
+	if polled == nil {
+		return nil
+	}
+
+	w.state = *polled
+	return w.save()
+}
+
+func (w *Watcher) wishlisted(items []Item) []Item {
+	wished := []Item{}
+	for _, item := range items {
+		for _, wish := range w.Wishlist {
+			if strings.Contains(strings.ToLower(item.Name), strings.ToLower(wish)) {
+				wished = append(wished, item)
+				break
+			}
+		}
+	}
+
+	return wished
+}
+
+func (w *Watcher) save() error {
+	data, err := json.MarshalIndent(w.state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(w.StatePath, data, 0644)
+}
+
+func rankChange(update player.LatestCompetitiveUpdate, tierMap map[int]string) *RankChange {
+	return &RankChange{
+		MatchID:        update.MatchID,
+		TierBefore:     update.TierBeforeUpdate,
+		TierAfter:      update.TierAfterUpdate,
+		TierNameBefore: tierMap[update.TierBeforeUpdate],
+		TierNameAfter:  tierMap[update.TierAfterUpdate],
+		RRBefore:       update.RankedRatingBeforeUpdate,
+		RRAfter:        update.RankedRatingAfterUpdate,
+		RREarned:       update.RankedRatingEarned,
+		Movement:       update.CompetitiveMovement,
+	}
+}
+
+func rankTitle(rank *RankChange) string {
+	switch {
+	case rank.TierAfter > rank.TierBefore:
+		return "Ranked up to " + rank.TierNameAfter
+	case rank.TierAfter < rank.TierBefore:
+		return "Deranked to " + rank.TierNameAfter
+	case rank.RREarned >= 0:
+		return fmt.Sprintf("Gained %d RR", rank.RREarned)
+	default:
+		return fmt.Sprintf("Lost %d RR", -rank.RREarned)
+	}
+}
+
+func sameItems(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
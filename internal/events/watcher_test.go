@@ -0,0 +1,238 @@
+package events
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/player"
+	"github.com/goamaan/valocli/internal/store"
+)
+
+const vp = "85ad13f7-3d1b-5128-9eb2-7cd8ee0b5741"
+
+// fakeRiot stands in for the storefront, the competitive updates and the rank names
+type fakeRiot struct {
+	daily       []string
+	nightMarket []string
+	matchID     string
+}
+
+func (f *fakeRiot) storeTable(c *core.Client) (*store.StoreCliTable, error) {
+	table := &store.StoreCliTable{
+		Currencies:           store.Currencies{vp: {ID: vp, DisplayName: "VP"}},
+		DailyStoreRemaining:  12 * time.Hour,
+		NightMarketRemaining: 5 * 24 * time.Hour,
+	}
+	for _, name := range f.daily {
+		table.DailyStore = append(table.DailyStore, store.Item{Item: name, Cost: store.Cost{vp: 1775}, DisplayIcon: "https://example.com/" + name})
+	}
+	for _, name := range f.nightMarket {
+		table.NightMarket = append(table.NightMarket, store.NightMarketItem{Item: name, DiscountCost: store.Cost{vp: 1065}, DiscountPercent: 40})
+	}
+
+	return table, nil
+}
+
+func (f *fakeRiot) mmr(c *core.Client, puuid string) (*player.PlayerMMRResponse, error) {
+	mmr := &player.PlayerMMRResponse{Subject: puuid}
+	if f.matchID != "" {
+		mmr.LatestCompetitiveUpdate = player.LatestCompetitiveUpdate{
+			MatchID:                  f.matchID,
+			TierBeforeUpdate:         11,
+			TierAfterUpdate:          12,
+			RankedRatingBeforeUpdate: 88,
+			RankedRatingAfterUpdate:  9,
+			RankedRatingEarned:       21,
+		}
+	}
+
+	return mmr, nil
+}
+
+func tiers() (map[int]string, error) {
+	return map[int]string{11: "Silver 3", 12: "Gold 1"}, nil
+}
+
+func newTestWatcher(t *testing.T, statePath string, riot *fakeRiot, wishlist ...string) *Watcher {
+	t.Helper()
+
+	w, err := NewWatcher(statePath, wishlist, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.fetchStore, w.fetchMMR, w.fetchTiers = riot.storeTable, riot.mmr, tiers
+
+	return w
+}
+
+// poll polls and delivers the events to the watcher's sinks
+func poll(t *testing.T, w *Watcher) []Event {
+	t.Helper()
+
+	events, err := w.Poll(core.New(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Dispatch(events); err != nil {
+		t.Fatal(err)
+	}
+
+	return events
+}
+
+func kinds(events []Event) []Kind {
+	kinds := []Kind{}
+	for _, event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+
+	return kinds
+}
+
+func TestPollTransitions(t *testing.T) {
+	riot := &fakeRiot{daily: []string{"Prime Vandal", "Reaver Sheriff"}, matchID: "match-1"}
+	w := newTestWatcher(t, filepath.Join(t.TempDir(), "events.json"), riot)
+
+	if events := poll(t, w); len(events) != 0 {
+		t.Fatalf("first poll sent %v, it should only remember what it saw", kinds(events))
+	}
+	if events := poll(t, w); len(events) != 0 {
+		t.Fatalf("nothing changed but got %v", kinds(events))
+	}
+
+	riot.daily = []string{"Glitchpop Phantom", "Reaver Sheriff"}
+	events := poll(t, w)
+	if len(events) != 1 || events[0].Kind != StoreRotated {
+		t.Fatalf("store rotation sent %v", kinds(events))
+	}
+	if items := events[0].Items; len(items) != 2 || items[0].Name != "Glitchpop Phantom" || items[0].Price != "1775 VP" ||
+		items[0].DisplayIcon != "https://example.com/Glitchpop Phantom" {
+		t.Errorf("rotated items %+v", items)
+	}
+
+	riot.nightMarket = []string{"Ion Operator"}
+	events = poll(t, w)
+	if len(events) != 1 || events[0].Kind != NightMarketOpened {
+		t.Fatalf("night market opening sent %v", kinds(events))
+	}
+	if items := events[0].Items; len(items) != 1 || items[0].Price != "1065 VP (-40%)" {
+		t.Errorf("night market items %+v", items)
+	}
+	if events = poll(t, w); len(events) != 0 {
+		t.Errorf("night market still open but got %v", kinds(events))
+	}
+
+	riot.matchID = "match-2"
+	events = poll(t, w)
+	if len(events) != 1 || events[0].Kind != RankChanged {
+		t.Fatalf("new competitive match sent %v", kinds(events))
+	}
+	if events[0].Title != "Ranked up to Gold 1" || events[0].Description != "Silver 3 88 RR → Gold 1 9 RR (+21)" {
+		t.Errorf("rank event %q: %q", events[0].Title, events[0].Description)
+	}
+	if rank := events[0].Rank; rank == nil || rank.MatchID != "match-2" || rank.RREarned != 21 {
+		t.Errorf("rank change %+v", rank)
+	}
+
+	riot.nightMarket = nil
+	if events = poll(t, w); len(events) != 0 {
+		t.Errorf("night market closing sent %v", kinds(events))
+	}
+	riot.nightMarket = []string{"Ion Operator"}
+	if events = poll(t, w); len(events) != 1 || events[0].Kind != NightMarketOpened {
+		t.Errorf("night market reopening sent %v", kinds(events))
+	}
+}
+
+func TestPollWishlist(t *testing.T) {
+	riot := &fakeRiot{daily: []string{"Prime Vandal", "Reaver Sheriff"}}
+	w := newTestWatcher(t, filepath.Join(t.TempDir(), "events.json"), riot, "vandal", "operator")
+
+	events := poll(t, w)
+	if len(events) != 1 || events[0].Kind != WishlistItem || events[0].Description != "Prime Vandal" {
+		t.Fatalf("first poll sent %+v, wishlisted items are always news", events)
+	}
+	if events = poll(t, w); len(events) != 0 {
+		t.Errorf("same store again sent %v", kinds(events))
+	}
+
+	riot.nightMarket = []string{"Ion Operator"}
+	events = poll(t, w)
+	if got := kinds(events); len(got) != 2 || got[0] != NightMarketOpened || got[1] != WishlistItem {
+		t.Fatalf("night market with a wishlisted item sent %v", got)
+	}
+	if events[1].Description != "Ion Operator" {
+		t.Errorf("wishlisted %q", events[1].Description)
+	}
+}
+
+func TestPollRemembersAcrossRuns(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "events.json")
+	riot := &fakeRiot{daily: []string{"Prime Vandal"}, matchID: "match-1"}
+
+	poll(t, newTestWatcher(t, statePath, riot))
+
+	riot.matchID = "match-2"
+	events := poll(t, newTestWatcher(t, statePath, riot))
+	if len(events) != 1 || events[0].Kind != RankChanged {
+		t.Errorf("restarted watcher sent %v, want only the rank change", kinds(events))
+	}
+}
+
+func TestPollWishlistIgnoresBlanks(t *testing.T) {
+	riot := &fakeRiot{daily: []string{"Prime Vandal", "Reaver Sheriff"}}
+	w := newTestWatcher(t, filepath.Join(t.TempDir(), "events.json"), riot, "", "  ", " reaver ")
+
+	if len(w.Wishlist) != 1 || w.Wishlist[0] != "reaver" {
+		t.Errorf("wishlist %q", w.Wishlist)
+	}
+
+	events := poll(t, w)
+	if len(events) != 1 || events[0].Description != "Reaver Sheriff" {
+		t.Errorf("got %+v, blank entries must not match every item", events)
+	}
+}
+
+func TestPollKeepsUndeliveredEvents(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "events.json")
+	riot := &fakeRiot{daily: []string{"Prime Vandal"}, matchID: "match-1"}
+	w := newTestWatcher(t, statePath, riot)
+	poll(t, w)
+
+	failing, _ := receiver(t, http.StatusBadGateway)
+	w.Sinks = []Sink{&WebhookSink{URL: failing.URL}}
+
+	riot.matchID = "match-2"
+	events, err := w.Poll(core.New(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Dispatch(events); err == nil {
+		t.Fatal("no error from a failing sink")
+	}
+
+	saved, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(saved), "match-2") {
+		t.Errorf("state saved with an undelivered rank change: %s", saved)
+	}
+
+	working, bodies := receiver(t, http.StatusNoContent)
+	w.Sinks = []Sink{&WebhookSink{URL: working.URL}}
+	if events := poll(t, w); len(events) != 1 || events[0].Kind != RankChanged {
+		t.Fatalf("next poll sent %v, want the rank change again", kinds(events))
+	}
+	if len(bodies) != 1 {
+		t.Errorf("got %d posts, want the rank change", len(bodies))
+	}
+	if events := poll(t, w); len(events) != 0 {
+		t.Errorf("delivered rank change sent again: %v", kinds(events))
+	}
+}
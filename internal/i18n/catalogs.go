@@ -0,0 +1,184 @@
+package i18n
+
+var catalogs = map[string]map[string]string{
+	"pt-BR": {
+		"Quantity":     "Quantidade",
+		"Bundle Price": "Preço no pacote",
+		"promo":        "promoção",
+		"Bundle price: %s (%s if bought separately, you save %s)": "Preço do pacote: %s (%s comprando separadamente, você economiza %s)",
+		"Bundle price: %s":                  "Preço do pacote: %s",
+		"Daily store":                       "Loja diária",
+		"Featured store":                    "Loja em destaque",
+		"Night Market":                      "Mercado Noturno",
+		"Accessories store":                 "Loja de acessórios",
+		"Night market is closed":            "O Mercado Noturno está fechado",
+		"⏳ Resets in %s (%s)":               "⏳ Reinicia em %s (%s)",
+		"Skin":                              "Skin",
+		"Item":                              "Item",
+		"Price":                             "Preço",
+		"Base Price":                        "Preço base",
+		"Discount Price":                    "Preço com desconto",
+		"Discount Percent":                  "Desconto (%)",
+		"Image Link":                        "Link da imagem",
+		"Free":                              "Grátis",
+		"Balances":                          "Saldos",
+		"Currency":                          "Moeda",
+		"Balance":                           "Saldo",
+		"Store":                             "Loja",
+		"Status":                            "Status",
+		"✅ affordable":                      "✅ dá para comprar",
+		"❌ %s short":                        "❌ faltam %s",
+		"💵 You have %s 💵":                   "💵 Você tem %s 💵",
+		"Your current rank: %s - %d/100 RR": "Seu ranque atual: %s - %d/100 RR",
+	},
+	"es-ES": {
+		"Quantity":     "Cantidad",
+		"Bundle Price": "Precio en el lote",
+		"promo":        "promoción",
+		"Bundle price: %s (%s if bought separately, you save %s)": "Precio del lote: %s (%s por separado, ahorras %s)",
+		"Bundle price: %s":                  "Precio del lote: %s",
+		"Daily store":                       "Tienda diaria",
+		"Featured store":                    "Tienda destacada",
+		"Night Market":                      "Mercado nocturno",
+		"Accessories store":                 "Tienda de accesorios",
+		"Night market is closed":            "El mercado nocturno está cerrado",
+		"⏳ Resets in %s (%s)":               "⏳ Se reinicia en %s (%s)",
+		"Skin":                              "Aspecto",
+		"Item":                              "Objeto",
+		"Price":                             "Precio",
+		"Base Price":                        "Precio base",
+		"Discount Price":                    "Precio con descuento",
+		"Discount Percent":                  "Descuento (%)",
+		"Image Link":                        "Enlace de imagen",
+		"Free":                              "Gratis",
+		"Balances":                          "Saldos",
+		"Currency":                          "Moneda",
+		"Balance":                           "Saldo",
+		"Store":                             "Tienda",
+		"Status":                            "Estado",
+		"✅ affordable":                      "✅ asequible",
+		"❌ %s short":                        "❌ faltan %s",
+		"💵 You have %s 💵":                   "💵 Tienes %s 💵",
+		"Your current rank: %s - %d/100 RR": "Tu rango actual: %s - %d/100 RR",
+	},
+	"ko-KR": {
+		"Quantity":     "수량",
+		"Bundle Price": "번들 가격",
+		"promo":        "프로모션",
+		"Bundle price: %s (%s if bought separately, you save %s)": "번들 가격: %s (개별 구매 시 %s, %s 절약)",
+		"Bundle price: %s":                  "번들 가격: %s",
+		"Daily store":                       "일일 상점",
+		"Featured store":                    "추천 상점",
+		"Night Market":                      "야시장",
+		"Accessories store":                 "액세서리 상점",
+		"Night market is closed":            "야시장이 열려 있지 않습니다",
+		"⏳ Resets in %s (%s)":               "⏳ %s 후 초기화 (%s)",
+		"Skin":                              "스킨",
+		"Item":                              "아이템",
+		"Price":                             "가격",
+		"Base Price":                        "기본 가격",
+		"Discount Price":                    "할인 가격",
+		"Discount Percent":                  "할인율",
+		"Image Link":                        "이미지 링크",
+		"Free":                              "무료",
+		"Balances":                          "잔액",
+		"Currency":                          "화폐",
+		"Balance":                           "잔액",
+		"Store":                             "상점",
+		"Status":                            "상태",
+		"✅ affordable":                      "✅ 구매 가능",
+		"❌ %s short":                        "❌ %s 부족",
+		"💵 You have %s 💵":                   "💵 보유: %s 💵",
+		"Your current rank: %s - %d/100 RR": "현재 랭크: %s - %d/100 RR",
+	},
+	"ja-JP": {
+		"Quantity":     "数量",
+		"Bundle Price": "バンドル価格",
+		"promo":        "プロモ",
+		"Bundle price: %s (%s if bought separately, you save %s)": "バンドル価格: %s (個別購入なら %s、%s お得)",
+		"Bundle price: %s":                  "バンドル価格: %s",
+		"Daily store":                       "デイリーストア",
+		"Featured store":                    "おすすめストア",
+		"Night Market":                      "ナイトマーケット",
+		"Accessories store":                 "アクセサリーストア",
+		"Night market is closed":            "ナイトマーケットは開催されていません",
+		"⏳ Resets in %s (%s)":               "⏳ リセットまで %s (%s)",
+		"Skin":                              "スキン",
+		"Item":                              "アイテム",
+		"Price":                             "価格",
+		"Base Price":                        "通常価格",
+		"Discount Price":                    "割引価格",
+		"Discount Percent":                  "割引率",
+		"Image Link":                        "画像リンク",
+		"Free":                              "無料",
+		"Balances":                          "残高",
+		"Currency":                          "通貨",
+		"Balance":                           "残高",
+		"Store":                             "ストア",
+		"Status":                            "状態",
+		"✅ affordable":                      "✅ 購入可能",
+		"❌ %s short":                        "❌ %s 不足",
+		"💵 You have %s 💵":                   "💵 所持: %s 💵",
+		"Your current rank: %s - %d/100 RR": "現在のランク: %s - %d/100 RR",
+	},
+	"fr-FR": {
+		"Quantity":     "Quantité",
+		"Bundle Price": "Prix en pack",
+		"promo":        "promo",
+		"Bundle price: %s (%s if bought separately, you save %s)": "Prix du pack : %s (%s à l'unité, vous économisez %s)",
+		"Bundle price: %s":                  "Prix du pack : %s",
+		"Daily store":                       "Boutique quotidienne",
+		"Featured store":                    "Boutique à la une",
+		"Night Market":                      "Marché nocturne",
+		"Accessories store":                 "Boutique d'accessoires",
+		"Night market is closed":            "Le marché nocturne est fermé",
+		"⏳ Resets in %s (%s)":               "⏳ Réinitialisation dans %s (%s)",
+		"Skin":                              "Skin",
+		"Item":                              "Objet",
+		"Price":                             "Prix",
+		"Base Price":                        "Prix de base",
+		"Discount Price":                    "Prix réduit",
+		"Discount Percent":                  "Réduction (%)",
+		"Image Link":                        "Lien de l'image",
+		"Free":                              "Gratuit",
+		"Balances":                          "Soldes",
+		"Currency":                          "Devise",
+		"Balance":                           "Solde",
+		"Store":                             "Boutique",
+		"Status":                            "Statut",
+		"✅ affordable":                      "✅ abordable",
+		"❌ %s short":                        "❌ il manque %s",
+		"💵 You have %s 💵":                   "💵 Vous avez %s 💵",
+		"Your current rank: %s - %d/100 RR": "Votre rang actuel : %s - %d/100 RR",
+	},
+	"de-DE": {
+		"Quantity":     "Menge",
+		"Bundle Price": "Paketpreis",
+		"promo":        "Aktion",
+		"Bundle price: %s (%s if bought separately, you save %s)": "Paketpreis: %s (%s einzeln gekauft, du sparst %s)",
+		"Bundle price: %s":                  "Paketpreis: %s",
+		"Daily store":                       "Täglicher Shop",
+		"Featured store":                    "Empfohlener Shop",
+		"Night Market":                      "Nachtmarkt",
+		"Accessories store":                 "Zubehör-Shop",
+		"Night market is closed":            "Der Nachtmarkt ist geschlossen",
+		"⏳ Resets in %s (%s)":               "⏳ Zurückgesetzt in %s (%s)",
+		"Skin":                              "Skin",
+		"Item":                              "Gegenstand",
+		"Price":                             "Preis",
+		"Base Price":                        "Grundpreis",
+		"Discount Price":                    "Rabattpreis",
+		"Discount Percent":                  "Rabatt (%)",
+		"Image Link":                        "Bildlink",
+		"Free":                              "Kostenlos",
+		"Balances":                          "Guthaben",
+		"Currency":                          "Währung",
+		"Balance":                           "Guthaben",
+		"Store":                             "Shop",
+		"Status":                            "Status",
+		"✅ affordable":                      "✅ leistbar",
+		"❌ %s short":                        "❌ %s fehlen",
+		"💵 You have %s 💵":                   "💵 Du hast %s 💵",
+		"Your current rank: %s - %d/100 RR": "Dein aktueller Rang: %s - %d/100 RR",
+	},
+}
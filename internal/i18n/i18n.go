@@ -0,0 +1,21 @@
+package i18n
+
+import "fmt"
+
+const DefaultLanguage = "en-US"
+
+// Language selects the message catalog used by T. Messages missing from a
+// catalog fall back to the english text, which is also the lookup key.
+var Language = DefaultLanguage
+
+func T(message string) string {
+	if translated, ok := catalogs[Language][message]; ok {
+		return translated
+	}
+
+	return message
+}
+
+func Tf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/player"
+	"github.com/goamaan/valocli/internal/store"
+)
+
+const (
+	DefaultAddr     = "127.0.0.1:9877"
+	DefaultInterval = 5 * time.Minute
+)
+
+// Collector periodically fetches the player's rank, wallet and progress into gauges, next to
+// counters for every request made to riot
+type Collector struct {
+	Registry *Registry
+
+	profile string
+	// do runs fn with valid tokens
+	do func(fn func(c *core.Client) error) error
+
+	tier            *Gauge
+	rr              *Gauge
+	wallet          *Gauge
+	accountLevel    *Gauge
+	battlepassTier  *Gauge
+	matchesPlayed   *Gauge
+	lastCollect     *Gauge
+	collectErrors   *Counter
+	requests        *Counter
+	requestErrors   *Counter
+	rateLimited     *Counter
+	requestDuration *Histogram
+}
+
+// NewCollector labels everything with profile, an empty profile uses the player's riot id
+func NewCollector(profile string, do func(fn func(c *core.Client) error) error) *Collector {
+	r := NewRegistry()
+	return &Collector{
+		Registry: r,
+		profile:  profile,
+		do:       do,
+
+		tier:           r.Gauge("valocli_competitive_tier", "Current competitive tier, 3 is Iron 1 and 27 Radiant", "profile", "region"),
+		rr:             r.Gauge("valocli_ranked_rating", "Ranked rating within the current tier", "profile", "region"),
+		wallet:         r.Gauge("valocli_wallet_balance", "Balance per currency", "profile", "region", "currency"),
+		accountLevel:   r.Gauge("valocli_account_level", "Account level", "profile", "region"),
+		battlepassTier: r.Gauge("valocli_battlepass_tier", "Tier of the current act's battlepass", "profile", "region"),
+		matchesPlayed:  r.Gauge("valocli_matches_played", "Matches played this act per queue", "profile", "region", "queue"),
+		lastCollect:    r.Gauge("valocli_last_collect_timestamp_seconds", "When the player data was last collected", "profile", "region"),
+		collectErrors:  r.Counter("valocli_collect_errors_total", "Player data that couldn't be collected", "metric"),
+
+		requests:        r.Counter("valocli_riot_requests_total", "Requests to riot by service and status code", "service", "method", "code"),
+		requestErrors:   r.Counter("valocli_riot_request_errors_total", "Requests to riot that failed or got an error status", "service", "method"),
+		rateLimited:     r.Counter("valocli_riot_rate_limited_total", "Requests riot answered with 429 Too Many Requests", "service"),
+		requestDuration: r.Histogram("valocli_riot_request_duration_seconds", "Latency of requests to riot", DefaultBuckets, "service", "method"),
+	}
+}
+
+func (collector *Collector) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	collector.Registry.ServeHTTP(w, req)
+}
+
+// Run collects right away and then every interval, it never returns
+func (collector *Collector) Run(interval time.Duration) {
+	for {
+		collector.Collect()
+		time.Sleep(interval)
+	}
+}
+
+// Collect updates every player gauge, a failing metric keeps its last value. Rejected tokens
+// stop the collection so do can refresh them and collect again
+func (collector *Collector) Collect() {
+	err := collector.do(func(c *core.Client) error {
+		profile := collector.profile
+		if profile == "" {
+			profile = player.Names(c).Name(c.AuthData.UserId)
+		}
+		labels := []string{profile, c.Region}
+
+		if err := collector.collect("mmr", func() error {
+			act, err := player.CurrentAct(c)
+			if err != nil {
+				return err
+			}
+
+			mmr, err := player.MMRFor(c, c.AuthData.UserId)
+			if err != nil {
+				return err
+			}
+
+			tier, rr := mmr.CurrentTier(act.ID)
+			collector.tier.Set(float64(tier), labels...)
+			collector.rr.Set(float64(rr), labels...)
+
+			collector.matchesPlayed.Reset()
+			for queue, skill := range mmr.QueueSkills {
+				games := skill.SeasonalInfoBySeasonID[act.ID].NumberOfGames
+				collector.matchesPlayed.Set(float64(games), profile, c.Region, queue)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if err := collector.collect("wallet", func() error {
+			wallet, err := store.FetchWallet(c)
+			if err != nil {
+				return err
+			}
+
+			currencies, err := store.GetCurrencies()
+			if err != nil {
+				return err
+			}
+
+			collector.wallet.Reset()
+			for id, amount := range wallet.Balances {
+				collector.wallet.Set(float64(amount), profile, c.Region, currencies.Name(id))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if err := collector.collect("account_level", func() error {
+			xp, err := player.FetchAccountXP(c)
+			if err != nil {
+				return err
+			}
+
+			collector.accountLevel.Set(float64(xp.Progress.Level), labels...)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if err := collector.collect("battlepass_tier", func() error {
+			contracts, err := player.GetContractProgress(c)
+			if err != nil {
+				return err
+			}
+
+			for _, contract := range contracts {
+				if contract.Kind == "Battlepass" {
+					collector.battlepassTier.Set(float64(contract.Tier), labels...)
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		collector.lastCollect.Set(float64(time.Now().Unix()), labels...)
+		return nil
+	})
+	if err != nil {
+		log.Printf("could not collect player data: %s", err)
+	}
+}
+
+// collect counts and logs a failing metric, only rejected tokens are returned
+func (collector *Collector) collect(metric string, fn func() error) error {
+	err := fn()
+	if errors.Is(err, core.ErrorRiotAuthentication) {
+		return err
+	}
+
+	if err != nil {
+		collector.collectErrors.Inc(metric)
+		log.Printf("could not collect %s: %s", metric, err)
+	}
+	return nil
+}
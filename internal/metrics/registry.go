@@ -0,0 +1,204 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry holds metric families and writes them in the Prometheus text format
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+}
+
+type kind string
+
+const (
+	kindGauge     kind = "gauge"
+	kindCounter   kind = "counter"
+	kindHistogram kind = "histogram"
+)
+
+type family struct {
+	name    string
+	help    string
+	kind    kind
+	labels  []string
+	buckets []float64
+
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	// histograms only
+	bucketCounts []uint64
+	count        uint64
+}
+
+type Gauge struct {
+	registry *Registry
+	family   *family
+}
+
+type Counter struct {
+	registry *Registry
+	family   *family
+}
+
+type Histogram struct {
+	registry *Registry
+	family   *family
+}
+
+// DefaultBuckets suit riot requests, which take from a few tens of milliseconds to seconds
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) add(name, help string, k kind, labels []string, buckets []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := &family{name: name, help: help, kind: k, labels: labels, buckets: buckets, series: map[string]*series{}}
+	r.families = append(r.families, f)
+	return f
+}
+
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{registry: r, family: r.add(name, help, kindGauge, labels, nil)}
+}
+
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{registry: r, family: r.add(name, help, kindCounter, labels, nil)}
+}
+
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return &Histogram{registry: r, family: r.add(name, help, kindHistogram, labels, buckets)}
+}
+
+// get returns the series for the label values, the registry lock must be held
+func (f *family) get(labelValues []string) *series {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metric %s wants %d label values, got %d", f.name, len(f.labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: labelValues, bucketCounts: make([]uint64, len(f.buckets))}
+		f.series[key] = s
+	}
+
+	return s
+}
+
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.registry.mu.Lock()
+	defer g.registry.mu.Unlock()
+
+	g.family.get(labelValues).value = value
+}
+
+// Reset drops every series, for gauges whose label values come and go
+func (g *Gauge) Reset() {
+	g.registry.mu.Lock()
+	defer g.registry.mu.Unlock()
+
+	g.family.series = map[string]*series{}
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.registry.mu.Lock()
+	defer c.registry.mu.Unlock()
+
+	c.family.get(labelValues).value++
+}
+
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.registry.mu.Lock()
+	defer h.registry.mu.Unlock()
+
+	s := h.family.get(labelValues)
+	s.value += value
+	s.count++
+	for i, bound := range h.family.buckets {
+		if value <= bound {
+			s.bucketCounts[i]++
+		}
+	}
+}
+
+// Write writes every metric in the Prometheus text exposition format
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, f := range r.families {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind); err != nil {
+			return err
+		}
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind != kindHistogram {
+				fmt.Fprintf(w, "%s%s %s\n", f.name, formatLabels(f.labels, s.labelValues), formatValue(s.value))
+				continue
+			}
+
+			bucketLabels := append(append([]string{}, f.labels...), "le")
+			for i, bound := range f.buckets {
+				labels := formatLabels(bucketLabels, append(append([]string{}, s.labelValues...), formatValue(bound)))
+				fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, labels, s.bucketCounts[i])
+			}
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, formatLabels(bucketLabels, append(append([]string{}, s.labelValues...), "+Inf")), s.count)
+			fmt.Fprintf(w, "%s_sum%s %s\n", f.name, formatLabels(f.labels, s.labelValues), formatValue(s.value))
+			fmt.Fprintf(w, "%s_count%s %d\n", f.name, formatLabels(f.labels, s.labelValues), s.count)
+		}
+	}
+
+	return nil
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.Write(w)
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, labelEscaper.Replace(values[i]))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
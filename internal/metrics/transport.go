@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+)
+
+// transport records latency, errors and rate limiting of every request riot answers
+type transport struct {
+	next      http.RoundTripper
+	collector *Collector
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	service := serviceOf(req.URL.Host)
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	t.collector.requestDuration.Observe(time.Since(start).Seconds(), service, req.Method)
+
+	if err != nil {
+		t.collector.requestErrors.Inc(service, req.Method)
+		return nil, err
+	}
+
+	t.collector.requests.Inc(service, req.Method, strconv.Itoa(res.StatusCode))
+	if res.StatusCode >= 400 {
+		t.collector.requestErrors.Inc(service, req.Method)
+	}
+	if res.StatusCode == http.StatusTooManyRequests {
+		t.collector.rateLimited.Inc(service)
+	}
+
+	return res, nil
+}
+
+// serviceOf names the riot service from the host, e.g. pd, glz, shared or auth, which keeps
+// the label small unlike paths full of puuids
+func serviceOf(host string) string {
+	name, _, _ := strings.Cut(host, ".")
+	if strings.HasPrefix(name, "glz-") {
+		return "glz"
+	}
+	if name == "" {
+		return "unknown"
+	}
+
+	return name
+}
+
+// Instrument records every request the client makes to riot
+func (collector *Collector) Instrument(c *core.Client) {
+	next := c.HttpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	c.HttpClient.Transport = &transport{next: next, collector: collector}
+}
@@ -0,0 +1,253 @@
+package player
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/goamaan/valocli/internal/content"
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	ContractsUrl = "https://pd.%s.a.pvp.net/contracts/v1/contracts/%s"
+)
+
+type ContractsResponse struct {
+	Version               int              `json:"Version"`
+	Subject               string           `json:"Subject"`
+	Contracts             []Contract       `json:"Contracts"`
+	ProcessedMatches      []ProcessedMatch `json:"ProcessedMatches"`
+	ActiveSpecialContract string           `json:"ActiveSpecialContract"`
+	Missions              []Mission        `json:"Missions"`
+	MissionMetadata       MissionMetadata  `json:"MissionMetadata"`
+}
+
+type Contract struct {
+	ContractDefinitionID string `json:"ContractDefinitionID"`
+	ContractProgression  struct {
+		TotalProgressionEarned        int `json:"TotalProgressionEarned"`
+		TotalProgressionEarnedVersion int `json:"TotalProgressionEarnedVersion"`
+	} `json:"ContractProgression"`
+	ProgressionLevelReached     int `json:"ProgressionLevelReached"`
+	ProgressionTowardsNextLevel int `json:"ProgressionTowardsNextLevel"`
+}
+
+type ProcessedMatch struct {
+	ID        string `json:"ID"`
+	StartTime int64  `json:"StartTime"`
+	XPGrants  *struct {
+		GamePlayed  int            `json:"GamePlayed"`
+		GameWon     int            `json:"GameWon"`
+		RoundPlayed int            `json:"RoundPlayed"`
+		RoundWon    int            `json:"RoundWon"`
+		Missions    map[string]int `json:"Missions"`
+	} `json:"XPGrants"`
+	ContractDeltas map[string]struct {
+		ID            string `json:"ID"`
+		TotalXPBefore int    `json:"TotalXPBefore"`
+		TotalXPAfter  int    `json:"TotalXPAfter"`
+	} `json:"ContractDeltas"`
+	CouldProgressMissions bool `json:"CouldProgressMissions"`
+}
+
+type Mission struct {
+	ID             string         `json:"ID"`
+	Objectives     map[string]int `json:"Objectives"`
+	Complete       bool           `json:"Complete"`
+	ExpirationTime time.Time      `json:"ExpirationTime"`
+}
+
+type MissionMetadata struct {
+	NPECompleted     bool      `json:"NPECompleted"`
+	WeeklyCheckpoint time.Time `json:"WeeklyCheckpoint"`
+	WeeklyRefillTime time.Time `json:"WeeklyRefillTime"`
+}
+
+type ContractProgress struct {
+	Name       string
+	Kind       string
+	Active     bool
+	Tier       int
+	TotalTiers int
+	XPIntoTier int
+	XPForTier  int
+	XPLeft     int
+
+	// estimates from recently processed matches, zero when there is no recent xp
+	XPPerMatch      float64
+	XPPerDay        float64
+	MatchesToFinish int
+	DaysToFinish    float64
+
+	EndsAt time.Time
+}
+
+func (p ContractProgress) Complete() bool {
+	return p.Tier >= p.TotalTiers
+}
+
+func (m ProcessedMatch) Started() time.Time {
+	return time.UnixMilli(m.StartTime)
+}
+
+func FetchContracts(c *core.Client) (*ContractsResponse, error) {
+	url := fmt.Sprintf(ContractsUrl, c.Shard(), c.AuthData.UserId)
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(ContractsResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// GetContractProgress returns the progress of the current act's battlepass, the active agent
+// contract and any other started agent contracts, battlepass first
+func GetContractProgress(c *core.Client) ([]ContractProgress, error) {
+	contracts, err := FetchContracts(c)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions, err := content.Contracts()
+	if err != nil {
+		return nil, err
+	}
+
+	act, err := CurrentAct(c)
+	if err != nil {
+		return nil, err
+	}
+
+	battlepass := []ContractProgress{}
+	agents := []ContractProgress{}
+	for _, contract := range contracts.Contracts {
+		definition, ok := definitions[contract.ContractDefinitionID]
+		if !ok {
+			continue
+		}
+
+		progress := contractProgress(contract, definition, contracts.ProcessedMatches)
+		progress.Active = contract.ContractDefinitionID == contracts.ActiveSpecialContract
+
+		switch definition.Content.RelationType {
+		case content.ContractRelationSeason:
+			if definition.Content.RelationUuid != act.ID {
+				continue
+			}
+			progress.Kind = "Battlepass"
+			progress.EndsAt = act.EndTime
+			battlepass = append(battlepass, progress)
+		case content.ContractRelationAgent:
+			if !progress.Active && (progress.Tier == 0 || progress.Complete()) {
+				continue
+			}
+			progress.Kind = "Agent"
+			agents = append(agents, progress)
+		}
+	}
+
+	return append(battlepass, agents...), nil
+}
+
+func contractProgress(contract Contract, definition content.ContractDefinition, matches []ProcessedMatch) ContractProgress {
+	levelXp := definition.LevelXp()
+	progress := ContractProgress{
+		Name:       definition.DisplayName,
+		Tier:       contract.ProgressionLevelReached,
+		TotalTiers: len(levelXp),
+		XPIntoTier: contract.ProgressionTowardsNextLevel,
+	}
+
+	if progress.Tier < len(levelXp) {
+		progress.XPForTier = levelXp[progress.Tier]
+		for _, xp := range levelXp[progress.Tier:] {
+			progress.XPLeft += xp
+		}
+		progress.XPLeft -= progress.XPIntoTier
+	}
+
+	earned, counted := 0, 0
+	oldest := time.Now()
+	for _, match := range matches {
+		delta, ok := match.ContractDeltas[contract.ContractDefinitionID]
+		if !ok || delta.TotalXPAfter <= delta.TotalXPBefore {
+			continue
+		}
+
+		earned += delta.TotalXPAfter - delta.TotalXPBefore
+		counted++
+		if start := match.Started(); start.Before(oldest) {
+			oldest = start
+		}
+	}
+
+	if counted == 0 || progress.XPLeft <= 0 {
+		return progress
+	}
+
+	days := math.Max(time.Since(oldest).Hours()/24, 1)
+	progress.XPPerMatch = float64(earned) / float64(counted)
+	progress.XPPerDay = float64(earned) / days
+	progress.MatchesToFinish = int(math.Ceil(float64(progress.XPLeft) / progress.XPPerMatch))
+	progress.DaysToFinish = float64(progress.XPLeft) / progress.XPPerDay
+
+	return progress
+}
+
+func GetContracts(c *core.Client) error {
+	progress, err := GetContractProgress(c)
+	if err != nil {
+		return err
+	}
+
+	PrintContracts(progress)
+	return nil
+}
+
+func PrintContracts(contracts []ContractProgress) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintln(w, "📜 Contracts 📜")
+	fmt.Fprintln(w, "Contract\tType\tTier\tXP into tier\tXP left\tEstimate")
+	for _, contract := range contracts {
+		name := contract.Name
+		if contract.Active {
+			name += " (active)"
+		}
+
+		tier := fmt.Sprintf("%d/%d", contract.Tier, contract.TotalTiers)
+		if contract.Complete() {
+			fmt.Fprintf(w, "%s\t%s\t%s\t-\t-\t✅ complete\n", name, contract.Kind, tier)
+			continue
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%d\t%s\n",
+			name, contract.Kind, tier, contract.XPIntoTier, contract.XPForTier, contract.XPLeft, contractEstimate(contract))
+	}
+	w.Flush()
+}
+
+func contractEstimate(contract ContractProgress) string {
+	if contract.XPPerMatch == 0 {
+		return "no recent xp"
+	}
+
+	estimate := fmt.Sprintf("~%d matches / %.0f days", contract.MatchesToFinish, math.Ceil(contract.DaysToFinish))
+	if contract.EndsAt.IsZero() {
+		return estimate
+	}
+
+	daysLeft := time.Until(contract.EndsAt).Hours() / 24
+	if contract.DaysToFinish <= daysLeft {
+		return fmt.Sprintf("%s, act ends in %.0f days ✅", estimate, daysLeft)
+	}
+
+	return fmt.Sprintf("%s, act ends in %.0f days ⚠️", estimate, daysLeft)
+}
@@ -0,0 +1,262 @@
+package player
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/goamaan/valocli/internal/content"
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	CoreGamePlayerUrl = "https://glz-%s-1.%s.a.pvp.net/core-game/v1/players/%s"
+	CoreGameMatchUrl  = "https://glz-%s-1.%s.a.pvp.net/core-game/v1/matches/%s"
+)
+
+type CoreGamePlayerResponse struct {
+	Subject string `json:"Subject"`
+	MatchID string `json:"MatchID"`
+	Version int64  `json:"Version"`
+}
+
+type CoreGameMatchResponse struct {
+	MatchID          string           `json:"MatchID"`
+	Version          int64            `json:"Version"`
+	State            string           `json:"State"`
+	MapID            string           `json:"MapID"`
+	ModeID           string           `json:"ModeID"`
+	ProvisioningFlow string           `json:"ProvisioningFlow"`
+	GamePodID        string           `json:"GamePodID"`
+	AllMUCName       string           `json:"AllMUCName"`
+	TeamMUCName      string           `json:"TeamMUCName"`
+	IsReconnectable  bool             `json:"IsReconnectable"`
+	Players          []CoreGamePlayer `json:"Players"`
+	MatchmakingData  *struct {
+		QueueID  string `json:"QueueID"`
+		IsRanked bool   `json:"IsRanked"`
+	} `json:"MatchmakingData"`
+}
+
+type CoreGamePlayer struct {
+	Subject           string            `json:"Subject"`
+	TeamID            string            `json:"TeamID"`
+	CharacterID       string            `json:"CharacterID"`
+	PlayerIdentity    PlayerIdentity    `json:"PlayerIdentity"`
+	SeasonalBadgeInfo SeasonalBadgeInfo `json:"SeasonalBadgeInfo"`
+	IsCoach           bool              `json:"IsCoach"`
+	IsAssociated      bool              `json:"IsAssociated"`
+}
+
+type LivePlayer struct {
+	Subject      string
+	IsSelf       bool
+	Name         string
+	Team         string
+	Agent        string
+	Level        string
+	Tier         int
+	TierName     string
+	RR           int
+	PeakTierName string
+	PeakAct      string
+	Wins         int
+	Games        int
+	MMRFailure   error
+}
+
+type LiveMatch struct {
+	MatchID string
+	Map     string
+	Queue   string
+	Players []LivePlayer
+}
+
+func (p LivePlayer) WinRate() string {
+	if p.Games == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d%% (%d/%d)", p.Wins*100/p.Games, p.Wins, p.Games)
+}
+
+func FetchCoreGamePlayer(c *core.Client, puuid string) (*CoreGamePlayerResponse, error) {
+	url := fmt.Sprintf(CoreGamePlayerUrl, c.Region, c.Shard(), puuid)
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(CoreGamePlayerResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func FetchCoreGameMatch(c *core.Client, matchId string) (*CoreGameMatchResponse, error) {
+	url := fmt.Sprintf(CoreGameMatchUrl, c.Region, c.Shard(), matchId)
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(CoreGameMatchResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// GetLiveMatch returns nil when the player isn't in a match
+func GetLiveMatch(c *core.Client) (*LiveMatch, error) {
+	corePlayer, err := FetchCoreGamePlayer(c, c.AuthData.UserId)
+	if err == core.ErrorRiotNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := FetchCoreGameMatch(c, corePlayer.MatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	tiers, err := GetActTiers(c)
+	if err != nil {
+		return nil, err
+	}
+	tierMap := tiers.Current
+
+	act, err := CurrentAct(c)
+	if err != nil {
+		return nil, err
+	}
+
+	actNames, err := ActNames(c)
+	if err != nil {
+		return nil, err
+	}
+
+	mapName, err := content.MapName(match.MapID)
+	if err != nil {
+		return nil, err
+	}
+
+	live := &LiveMatch{MatchID: match.MatchID, Map: mapName}
+	if match.MatchmakingData != nil {
+		live.Queue = QueueName(match.MatchmakingData.QueueID)
+	}
+
+	puuids := []string{}
+	for _, p := range match.Players {
+		puuids = append(puuids, p.Subject)
+	}
+
+	names, err := Names(c).Resolve(puuids)
+	if err != nil {
+		return nil, err
+	}
+	mmrs := MMRForAll(c, puuids)
+
+	selfTeam := ""
+	for _, p := range match.Players {
+		if p.Subject == c.AuthData.UserId {
+			selfTeam = p.TeamID
+		}
+	}
+
+	for _, p := range match.Players {
+		agent, err := content.AgentName(p.CharacterID)
+		if err != nil {
+			return nil, err
+		}
+
+		player := LivePlayer{
+			Subject: p.Subject,
+			IsSelf:  p.Subject == c.AuthData.UserId,
+			Name:    names[p.Subject].RiotId(),
+			Team:    p.TeamID,
+			Agent:   agent,
+			Level:   p.PlayerIdentity.Level(),
+		}
+
+		if p.PlayerIdentity.Incognito && !player.IsSelf {
+			player.Name = "(hidden)"
+		}
+
+		if result := mmrs[p.Subject]; result.Err != nil {
+			player.MMRFailure = result.Err
+		} else {
+			mmr := result.MMR
+			player.Tier, player.RR = mmr.CurrentTier(act.ID)
+			info := mmr.QueueSkills[CompetitiveQueue].SeasonalInfoBySeasonID[act.ID]
+			player.Wins, player.Games = info.NumberOfWins, info.NumberOfGames
+
+			peakTier, peakSeason := mmr.PeakTier(tiers)
+			player.PeakTierName = tierMap[peakTier]
+			player.PeakAct = actNames[peakSeason]
+		}
+
+		player.TierName = tierMap[player.Tier]
+		live.Players = append(live.Players, player)
+	}
+
+	// own team first, then by rank
+	sort.SliceStable(live.Players, func(i, j int) bool {
+		iAlly, jAlly := live.Players[i].Team == selfTeam, live.Players[j].Team == selfTeam
+		if iAlly != jAlly {
+			return iAlly
+		}
+		return live.Players[i].Tier > live.Players[j].Tier
+	})
+
+	return live, nil
+}
+
+func GetLive(c *core.Client) error {
+	live, err := GetLiveMatch(c)
+	if err != nil {
+		return err
+	}
+
+	PrintLive(live)
+	return nil
+}
+
+func PrintLive(live *LiveMatch) {
+	if live == nil {
+		fmt.Println("You are not in a match")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintf(w, "⚔️ Live match - %s - %s ⚔️\n", live.Map, live.Queue)
+	fmt.Fprintln(w, "Team\tAgent\tName\tRank\tRR\tPeak Rank\tLevel\tWin Rate (act)")
+	for _, p := range live.Players {
+		name := p.Name
+		if p.IsSelf {
+			name += " (you)"
+		}
+
+		peak := "-"
+		if p.PeakTierName != "" {
+			peak = p.PeakTierName
+			if p.PeakAct != "" {
+				peak = fmt.Sprintf("%s (%s)", p.PeakTierName, p.PeakAct)
+			}
+		}
+
+		rank := p.TierName
+		if p.MMRFailure != nil {
+			rank = "(rank lookup failed)"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n", p.Team, p.Agent, name, rank, p.RR, peak, p.Level, p.WinRate())
+	}
+	w.Flush()
+}
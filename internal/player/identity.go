@@ -0,0 +1,54 @@
+package player
+
+import (
+	"strconv"
+	"strings"
+)
+
+type PlayerIdentity struct {
+	Subject                string `json:"Subject"`
+	PlayerCardID           string `json:"PlayerCardID"`
+	PlayerTitleID          string `json:"PlayerTitleID"`
+	AccountLevel           int    `json:"AccountLevel"`
+	PreferredLevelBorderID string `json:"PreferredLevelBorderID"`
+	Incognito              bool   `json:"Incognito"`
+	HideAccountLevel       bool   `json:"HideAccountLevel"`
+}
+
+type SeasonalBadgeInfo struct {
+	SeasonID        string         `json:"SeasonID"`
+	NumberOfWins    int            `json:"NumberOfWins"`
+	WinsByTier      map[string]int `json:"WinsByTier"`
+	Rank            int            `json:"Rank"`
+	LeaderboardRank int            `json:"LeaderboardRank"`
+}
+
+var QueueNames = map[string]string{
+	"":            "Custom",
+	"competitive": "Competitive",
+	"unrated":     "Unrated",
+	"swiftplay":   "Swiftplay",
+	"spikerush":   "Spike Rush",
+	"deathmatch":  "Deathmatch",
+	"hurm":        "Team Deathmatch",
+	"ggteam":      "Escalation",
+	"onefa":       "Replication",
+	"premier":     "Premier",
+	"newmap":      "New Map",
+}
+
+func QueueName(queueId string) string {
+	if name, ok := QueueNames[queueId]; ok {
+		return name
+	}
+
+	return strings.ToUpper(queueId[:1]) + queueId[1:]
+}
+
+func (identity PlayerIdentity) Level() string {
+	if identity.HideAccountLevel {
+		return "hidden"
+	}
+
+	return strconv.Itoa(identity.AccountLevel)
+}
@@ -0,0 +1,198 @@
+package player
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	LeaderboardUrl = "https://pd.%s.a.pvp.net/mmr/v1/leaderboards/affinity/%s/queue/competitive/season/%s?startIndex=%d&size=%d"
+
+	DefaultLeaderboardSize = 25
+)
+
+type LeaderboardResponse struct {
+	Deployment            string              `json:"Deployment"`
+	QueueID               string              `json:"QueueID"`
+	SeasonID              string              `json:"SeasonID"`
+	Players               []LeaderboardPlayer `json:"Players"`
+	TotalPlayers          int                 `json:"totalPlayers"`
+	ImmortalStartingPage  int                 `json:"immortalStartingPage"`
+	ImmortalStartingIndex int                 `json:"immortalStartingIndex"`
+	TopTierRRThreshold    int                 `json:"topTierRRThreshold"`
+	StartIndex            int                 `json:"startIndex"`
+	Query                 string              `json:"query"`
+}
+
+type LeaderboardPlayer struct {
+	PlayerCardID    string `json:"PlayerCardID"`
+	TitleID         string `json:"TitleID"`
+	IsBanned        bool   `json:"IsBanned"`
+	IsAnonymized    bool   `json:"IsAnonymized"`
+	Puuid           string `json:"puuid"`
+	GameName        string `json:"gameName"`
+	TagLine         string `json:"tagLine"`
+	LeaderboardRank int    `json:"leaderboardRank"`
+	RankedRating    int    `json:"rankedRating"`
+	NumberOfWins    int    `json:"numberOfWins"`
+	CompetitiveTier int    `json:"competitiveTier"`
+}
+
+func (p LeaderboardPlayer) RiotId() string {
+	if p.IsAnonymized || p.GameName == "" {
+		return "Secret Agent"
+	}
+
+	return fmt.Sprintf("%s#%s", p.GameName, p.TagLine)
+}
+
+// Leaderboard returns size players of the region's ranked leaderboard from start (0 based),
+// only the ones whose name matches query if it's set. An empty seasonID means the current act.
+func Leaderboard(c *core.Client, seasonID string, start, size int, query string) (*LeaderboardResponse, error) {
+	if seasonID == "" {
+		act, err := CurrentAct(c)
+		if err != nil {
+			return nil, err
+		}
+		seasonID = act.ID
+	}
+
+	leaderboardUrl := fmt.Sprintf(LeaderboardUrl, c.Shard(), c.Region, seasonID, start, size)
+	if query != "" {
+		leaderboardUrl += "&query=" + url.QueryEscape(query)
+	}
+
+	req, err := c.RequestWithClientInfo("GET", leaderboardUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(LeaderboardResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// LeaderboardPositions returns the leaderboard rank in the season of each of the players
+// that are on it, taken from their MMR
+func LeaderboardPositions(c *core.Client, seasonID string, puuids []string) map[string]int {
+	positions := map[string]int{}
+	for puuid, result := range MMRForAll(c, puuids) {
+		if result.Err != nil {
+			continue
+		}
+
+		if rank := result.MMR.QueueSkills[CompetitiveQueue].SeasonalInfoBySeasonID[seasonID].LeaderboardRank; rank > 0 {
+			positions[puuid] = rank
+		}
+	}
+
+	return positions
+}
+
+// GetLeaderboard prints a page (1 based) of the leaderboard, or the page the player is on
+// when aroundSelf is set, followed by where the player and their friends are. friends maps
+// puuids to riot ids.
+func GetLeaderboard(c *core.Client, seasonID string, page, size int, query string, aroundSelf bool, friends map[string]string) error {
+	if seasonID == "" {
+		act, err := CurrentAct(c)
+		if err != nil {
+			return err
+		}
+		seasonID = act.ID
+	}
+
+	actNames, err := ActNames(c)
+	if err != nil {
+		return err
+	}
+
+	self := c.AuthData.UserId
+	puuids := []string{self}
+	for puuid := range friends {
+		puuids = append(puuids, puuid)
+	}
+	positions := LeaderboardPositions(c, seasonID, puuids)
+
+	start := (page - 1) * size
+	if aroundSelf {
+		if rank, ok := positions[self]; ok {
+			start = (rank - 1) / size * size
+		} else {
+			fmt.Printf("Couldn't find your position on the leaderboard of this act, showing page %d instead\n", page)
+		}
+	}
+
+	board, err := Leaderboard(c, seasonID, start, size, query)
+	if err != nil {
+		return err
+	}
+
+	if err = PrintLeaderboard(board, actNames[seasonID], self, friends); err != nil {
+		return err
+	}
+
+	PrintLeaderboardPositions(positions, self, friends)
+	return nil
+}
+
+// PrintLeaderboard prints a page of the leaderboard, marking the player and their friends
+func PrintLeaderboard(board *LeaderboardResponse, actName, self string, friends map[string]string) error {
+	tierMap, err := core.GetCompetitiveTiers()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	title := fmt.Sprintf("🏆 Leaderboard - %s - %d players 🏆", actName, board.TotalPlayers)
+	if board.Query != "" {
+		title = fmt.Sprintf("🏆 Leaderboard - %s - players matching %q 🏆", actName, board.Query)
+	}
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, "#\tPlayer\tRank\tRR\tWins")
+	for _, p := range board.Players {
+		name := p.RiotId()
+		switch {
+		case p.Puuid != "" && p.Puuid == self:
+			name = "⭐ " + name + " (you)"
+		case friends[p.Puuid] != "":
+			name = "🫂 " + name
+		}
+		if p.IsBanned {
+			name += " (banned)"
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\n", p.LeaderboardRank, name, tierMap[p.CompetitiveTier], p.RankedRating, p.NumberOfWins)
+	}
+	w.Flush()
+
+	return nil
+}
+
+// PrintLeaderboardPositions lists where the player and their friends are on the leaderboard
+func PrintLeaderboardPositions(positions map[string]int, self string, friends map[string]string) {
+	if rank, ok := positions[self]; ok {
+		fmt.Printf("⭐ You are #%d\n", rank)
+	} else {
+		fmt.Println("⭐ You are not on the leaderboard")
+	}
+
+	ranked := []string{}
+	for puuid := range friends {
+		if _, ok := positions[puuid]; ok {
+			ranked = append(ranked, puuid)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool { return positions[ranked[i]] < positions[ranked[j]] })
+
+	for _, puuid := range ranked {
+		fmt.Printf("🫂 %s is #%d\n", friends[puuid], positions[puuid])
+	}
+}
@@ -0,0 +1,268 @@
+package player
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/goamaan/valocli/internal/content"
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/store"
+)
+
+const (
+	PlayerLoadoutUrl = "https://pd.%s.a.pvp.net/personalization/v2/players/%s/playerloadout"
+)
+
+var SpraySlotNames = map[string]string{
+	"0814b2fe-4512-60a4-5288-1fbdcec6ca48": "Pre-round",
+	"04af080a-4071-487b-61c0-5b9c0cfaac74": "Mid-round",
+	"5863985e-43ac-b05d-cb2d-139e72970014": "Post-round",
+}
+
+var weaponCategoryOrder = map[string]int{
+	"EEquippableCategory::Sidearm": 0,
+	"EEquippableCategory::SMG":     1,
+	"EEquippableCategory::Shotgun": 2,
+	"EEquippableCategory::Rifle":   3,
+	"EEquippableCategory::Sniper":  4,
+	"EEquippableCategory::Heavy":   5,
+	"EEquippableCategory::Melee":   6,
+}
+
+type PlayerLoadoutResponse struct {
+	Subject   string          `json:"Subject"`
+	Version   int             `json:"Version"`
+	Guns      []LoadoutGun    `json:"Guns"`
+	Sprays    []LoadoutSpray  `json:"Sprays"`
+	Identity  LoadoutIdentity `json:"Identity"`
+	Incognito bool            `json:"Incognito"`
+}
+
+type LoadoutGun struct {
+	ID              string            `json:"ID"`
+	CharmInstanceID string            `json:"CharmInstanceID,omitempty"`
+	CharmID         string            `json:"CharmID,omitempty"`
+	CharmLevelID    string            `json:"CharmLevelID,omitempty"`
+	SkinID          string            `json:"SkinID"`
+	SkinLevelID     string            `json:"SkinLevelID"`
+	ChromaID        string            `json:"ChromaID"`
+	Attachments     []json.RawMessage `json:"Attachments"`
+}
+
+type LoadoutSpray struct {
+	EquipSlotID  string  `json:"EquipSlotID"`
+	SprayID      string  `json:"SprayID"`
+	SprayLevelID *string `json:"SprayLevelID"`
+}
+
+type LoadoutIdentity struct {
+	PlayerCardID           string `json:"PlayerCardID"`
+	PlayerTitleID          string `json:"PlayerTitleID"`
+	AccountLevel           int    `json:"AccountLevel"`
+	PreferredLevelBorderID string `json:"PreferredLevelBorderID"`
+	HideAccountLevel       bool   `json:"HideAccountLevel"`
+}
+
+type LoadoutWeapon struct {
+	ID        string
+	Weapon    string
+	Category  string
+	Skin      string
+	SkinLevel string
+	Chroma    string
+	Buddy     string
+	Icon      string
+}
+
+type LoadoutSprayView struct {
+	SlotID string
+	Slot   string
+	Spray  string
+}
+
+// PlayerLoadout is a loadout with every item resolved to its display name
+type PlayerLoadout struct {
+	Subject      string
+	Weapons      []LoadoutWeapon
+	Sprays       []LoadoutSprayView
+	PlayerCard   string
+	PlayerTitle  string
+	LevelBorder  string
+	AccountLevel int
+
+	Raw *PlayerLoadoutResponse
+}
+
+func FetchPlayerLoadout(c *core.Client, puuid string) (*PlayerLoadoutResponse, error) {
+	url := fmt.Sprintf(PlayerLoadoutUrl, c.Shard(), puuid)
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(PlayerLoadoutResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// Loadout fetches a player's equipped cosmetics and resolves them through the content catalog
+func Loadout(c *core.Client, puuid string) (*PlayerLoadout, error) {
+	raw, err := FetchPlayerLoadout(c, puuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return ResolveLoadout(raw)
+}
+
+func ResolveLoadout(raw *PlayerLoadoutResponse) (*PlayerLoadout, error) {
+	weapons, err := content.Weapons()
+	if err != nil {
+		return nil, err
+	}
+
+	loadout := &PlayerLoadout{Subject: raw.Subject, AccountLevel: raw.Identity.AccountLevel, Raw: raw}
+
+	for _, gun := range raw.Guns {
+		weapon := weapons[gun.ID]
+		view := LoadoutWeapon{ID: gun.ID, Weapon: weapon.DisplayName, Category: weapon.Category, Skin: "-", Buddy: "-"}
+
+		if skin := weapon.Skin(gun.SkinID); skin != nil {
+			view.Skin = skin.DisplayName
+		}
+
+		var levelIcon, chromaIcon string
+		if view.SkinLevel, levelIcon, err = itemName(store.SkinsId, gun.SkinLevelID); err != nil {
+			return nil, err
+		}
+		if view.Chroma, chromaIcon, err = itemName(store.SkinVariantsId, gun.ChromaID); err != nil {
+			return nil, err
+		}
+
+		view.Icon = chromaIcon
+		if view.Icon == "" {
+			view.Icon = levelIcon
+		}
+		if gun.CharmLevelID != "" {
+			if view.Buddy, _, err = itemName(store.GunBuddiesId, gun.CharmLevelID); err != nil {
+				return nil, err
+			}
+		}
+
+		loadout.Weapons = append(loadout.Weapons, view)
+	}
+
+	sort.SliceStable(loadout.Weapons, func(i, j int) bool {
+		iOrder, jOrder := weaponCategoryOrder[loadout.Weapons[i].Category], weaponCategoryOrder[loadout.Weapons[j].Category]
+		if iOrder != jOrder {
+			return iOrder < jOrder
+		}
+		return loadout.Weapons[i].Weapon < loadout.Weapons[j].Weapon
+	})
+
+	for _, spray := range raw.Sprays {
+		name, _, err := itemName(store.SpraysId, spray.SprayID)
+		if err != nil {
+			return nil, err
+		}
+
+		slot, ok := SpraySlotNames[spray.EquipSlotID]
+		if !ok {
+			slot = "Other"
+		}
+
+		loadout.Sprays = append(loadout.Sprays, LoadoutSprayView{SlotID: spray.EquipSlotID, Slot: slot, Spray: name})
+	}
+
+	if loadout.PlayerCard, _, err = itemName(store.CardsId, raw.Identity.PlayerCardID); err != nil {
+		return nil, err
+	}
+	if loadout.PlayerTitle, _, err = itemName(store.TitlesId, raw.Identity.PlayerTitleID); err != nil {
+		return nil, err
+	}
+
+	if raw.Identity.PreferredLevelBorderID != "" {
+		border, err := content.LevelBorderByID(raw.Identity.PreferredLevelBorderID)
+		if err != nil {
+			return nil, err
+		}
+		loadout.LevelBorder = fmt.Sprintf("Level %d border", border.StartingLevel)
+	}
+
+	return loadout, nil
+}
+
+func itemName(itemTypeId, itemId string) (string, string, error) {
+	if itemId == "" {
+		return "-", "", nil
+	}
+
+	item, err := store.FetchItem(itemTypeId, itemId)
+	if err != nil {
+		return "", "", err
+	}
+
+	return item.DisplayName, item.DisplayIcon, nil
+}
+
+func PutPlayerLoadout(c *core.Client, loadout *PlayerLoadoutResponse) (*PlayerLoadoutResponse, error) {
+	body, err := json.Marshal(loadout)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf(PlayerLoadoutUrl, c.Shard(), c.AuthData.UserId)
+	req, err := c.RequestWithClientInfo("PUT", url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	updated := new(PlayerLoadoutResponse)
+	if err = c.DoJSON(req, updated); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func GetLoadout(c *core.Client) error {
+	loadout, err := Loadout(c, c.AuthData.UserId)
+	if err != nil {
+		return err
+	}
+
+	PrintLoadout(loadout)
+	return nil
+}
+
+func PrintLoadout(loadout *PlayerLoadout) {
+	FprintLoadout(os.Stdout, loadout)
+}
+
+func FprintLoadout(out io.Writer, loadout *PlayerLoadout) {
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintln(w, "🔫 Loadout 🔫")
+	fmt.Fprintln(w, "Weapon\tSkin\tLevel\tChroma\tBuddy\tImage Link")
+	for _, weapon := range loadout.Weapons {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", weapon.Weapon, weapon.Skin, weapon.SkinLevel, weapon.Chroma, weapon.Buddy, weapon.Icon)
+	}
+	fmt.Fprintln(w, "★★★★★★★★★★★★★★★★")
+	fmt.Fprintln(w, "Spray slot\tSpray")
+	for _, spray := range loadout.Sprays {
+		fmt.Fprintf(w, "%s\t%s\n", spray.Slot, spray.Spray)
+	}
+	fmt.Fprintln(w, "★★★★★★★★★★★★★★★★")
+	fmt.Fprintf(w, "Player card\t%s\n", loadout.PlayerCard)
+	fmt.Fprintf(w, "Title\t%s\n", loadout.PlayerTitle)
+	fmt.Fprintf(w, "Level border\t%s\n", loadout.LevelBorder)
+	fmt.Fprintf(w, "Account level\t%d\n", loadout.AccountLevel)
+	w.Flush()
+}
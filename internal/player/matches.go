@@ -0,0 +1,255 @@
+package player
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/goamaan/valocli/internal/content"
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	MatchHistoryUrl = "https://pd.%s.a.pvp.net/match-history/v1/history/%s?startIndex=%d&endIndex=%d"
+	MatchDetailsUrl = "https://pd.%s.a.pvp.net/match-details/v1/matches/%s"
+
+	// match details never change, and riot rate limits them like the mmr endpoint
+	matchDetailsConcurrency = 4
+)
+
+type MatchHistoryResponse struct {
+	Subject    string `json:"Subject"`
+	BeginIndex int    `json:"BeginIndex"`
+	EndIndex   int    `json:"EndIndex"`
+	Total      int    `json:"Total"`
+	History    []struct {
+		MatchID       string `json:"MatchID"`
+		GameStartTime int64  `json:"GameStartTime"`
+		QueueID       string `json:"QueueID"`
+	} `json:"History"`
+}
+
+type MatchDetailsResponse struct {
+	MatchInfo struct {
+		MatchID          string `json:"matchId"`
+		MapID            string `json:"mapId"`
+		GameStartMillis  int64  `json:"gameStartMillis"`
+		GameLengthMillis int64  `json:"gameLengthMillis"`
+		QueueID          string `json:"queueID"`
+		IsRanked         bool   `json:"isRanked"`
+		SeasonID         string `json:"seasonId"`
+		IsCompleted      bool   `json:"isCompleted"`
+	} `json:"matchInfo"`
+	Players []struct {
+		Subject         string `json:"subject"`
+		GameName        string `json:"gameName"`
+		TagLine         string `json:"tagLine"`
+		TeamID          string `json:"teamId"`
+		PartyID         string `json:"partyId"`
+		CharacterID     string `json:"characterId"`
+		CompetitiveTier int    `json:"competitiveTier"`
+		Stats           *struct {
+			Score        int `json:"score"`
+			RoundsPlayed int `json:"roundsPlayed"`
+			Kills        int `json:"kills"`
+			Deaths       int `json:"deaths"`
+			Assists      int `json:"assists"`
+		} `json:"stats"`
+	} `json:"players"`
+	Teams []struct {
+		TeamID       string `json:"teamId"`
+		Won          bool   `json:"won"`
+		RoundsPlayed int    `json:"roundsPlayed"`
+		RoundsWon    int    `json:"roundsWon"`
+	} `json:"teams"`
+}
+
+// MatchSummary is a finished match from one player's point of view
+type MatchSummary struct {
+	MatchID    string
+	Start      time.Time
+	Queue      string
+	Map        string
+	Agent      string
+	Result     string
+	RoundsWon  int
+	RoundsLost int
+	Kills      int
+	Deaths     int
+	Assists    int
+	Tier       int
+}
+
+// completed matches kept in memory, match details are large and a long running server or
+// interface would otherwise keep every match it ever showed
+const maxCachedMatches = 50
+
+var (
+	matchDetailsMu    sync.Mutex
+	matchDetailsCache = map[string]*MatchDetailsResponse{}
+	// cached match ids, oldest first
+	matchDetailsOrder []string
+)
+
+// FetchMatchHistory returns the matches of a player between start and end (0 based, most
+// recent first), only matches of queue if it's set
+func FetchMatchHistory(c *core.Client, puuid string, start, end int, queue string) (*MatchHistoryResponse, error) {
+	historyUrl := fmt.Sprintf(MatchHistoryUrl, c.Shard(), puuid, start, end)
+	if queue != "" {
+		historyUrl += "&queue=" + url.QueryEscape(queue)
+	}
+
+	req, err := c.RequestWithClientInfo("GET", historyUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(MatchHistoryResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// FetchMatchDetails returns the details of a match, the last few completed ones are cached
+func FetchMatchDetails(c *core.Client, matchId string) (*MatchDetailsResponse, error) {
+	matchDetailsMu.Lock()
+	cached, ok := matchDetailsCache[matchId]
+	matchDetailsMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	req, err := c.RequestWithClientInfo("GET", fmt.Sprintf(MatchDetailsUrl, c.Shard(), matchId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(MatchDetailsResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	if body.MatchInfo.IsCompleted {
+		cacheMatchDetails(matchId, body)
+	}
+
+	return body, nil
+}
+
+func cacheMatchDetails(matchId string, details *MatchDetailsResponse) {
+	matchDetailsMu.Lock()
+	defer matchDetailsMu.Unlock()
+
+	if _, ok := matchDetailsCache[matchId]; ok {
+		return
+	}
+
+	matchDetailsCache[matchId] = details
+	matchDetailsOrder = append(matchDetailsOrder, matchId)
+	for len(matchDetailsOrder) > maxCachedMatches {
+		delete(matchDetailsCache, matchDetailsOrder[0])
+		matchDetailsOrder = matchDetailsOrder[1:]
+	}
+}
+
+// RecentMatches summarizes the last count matches of a player, only matches of queue if it's set
+func RecentMatches(c *core.Client, puuid, queue string, count int) ([]MatchSummary, error) {
+	history, err := FetchMatchHistory(c, puuid, 0, count, queue)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]MatchSummary, len(history.History))
+	errs := make([]error, len(history.History))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, matchDetailsConcurrency)
+
+	for i, match := range history.History {
+		wg.Add(1)
+		go func(i int, matchId string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			details, err := FetchMatchDetails(c, matchId)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			summaries[i] = details.Summary(puuid)
+		}(i, match.MatchID)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return summaries, nil
+}
+
+// Summary describes the match from the point of view of the player with the puuid
+func (m *MatchDetailsResponse) Summary(puuid string) MatchSummary {
+	summary := MatchSummary{
+		MatchID: m.MatchInfo.MatchID,
+		Start:   time.UnixMilli(m.MatchInfo.GameStartMillis),
+		Queue:   QueueName(m.MatchInfo.QueueID),
+	}
+	summary.Map, _ = content.MapName(m.MatchInfo.MapID)
+
+	teamId := ""
+	for _, p := range m.Players {
+		if p.Subject != puuid {
+			continue
+		}
+
+		teamId = p.TeamID
+		summary.Tier = p.CompetitiveTier
+		summary.Agent, _ = content.AgentName(p.CharacterID)
+		if p.Stats != nil {
+			summary.Kills, summary.Deaths, summary.Assists = p.Stats.Kills, p.Stats.Deaths, p.Stats.Assists
+		}
+	}
+
+	summary.Result = "Draw"
+	for _, team := range m.Teams {
+		if team.TeamID == teamId {
+			summary.RoundsWon = team.RoundsWon
+			if team.Won {
+				summary.Result = "Win"
+			}
+		} else {
+			summary.RoundsLost = team.RoundsWon
+			if team.Won {
+				summary.Result = "Loss"
+			}
+		}
+	}
+
+	return summary
+}
+
+func FprintMatches(out io.Writer, matches []MatchSummary) {
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintln(w, "⚔️ Recent matches ⚔️")
+	fmt.Fprintln(w, "Date\tQueue\tMap\tAgent\tResult\tScore\tK/D/A")
+	for _, match := range matches {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", match.Start.Local().Format("Mon 02 Jan 15:04"), match.Queue, match.Map, match.Agent, match.Result, match.Score(), match.KDA())
+	}
+	w.Flush()
+}
+
+func (s MatchSummary) Score() string {
+	return fmt.Sprintf("%d-%d", s.RoundsWon, s.RoundsLost)
+}
+
+func (s MatchSummary) KDA() string {
+	return fmt.Sprintf("%d/%d/%d", s.Kills, s.Deaths, s.Assists)
+}
@@ -0,0 +1,164 @@
+package player
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/goamaan/valocli/internal/content"
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/store"
+)
+
+const (
+	MissionTypeDaily  = "Daily"
+	MissionTypeWeekly = "Weekly"
+)
+
+var missionTypeOrder = map[string]int{
+	MissionTypeDaily:  0,
+	MissionTypeWeekly: 1,
+}
+
+type MissionProgress struct {
+	Type        string
+	Description string
+	Progress    int
+	Goal        int
+	XP          int
+	Complete    bool
+	ExpiresAt   time.Time
+}
+
+type MissionsOverview struct {
+	Missions         []MissionProgress
+	WeeklyRefillTime time.Time
+}
+
+// GetMissionProgress resolves the active missions of the contracts response through the content catalog
+func GetMissionProgress(c *core.Client) (*MissionsOverview, error) {
+	contracts, err := FetchContracts(c)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions, err := content.Missions()
+	if err != nil {
+		return nil, err
+	}
+
+	objectives, err := content.Objectives()
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &MissionsOverview{WeeklyRefillTime: contracts.MissionMetadata.WeeklyRefillTime}
+	for _, mission := range contracts.Missions {
+		definition := definitions[mission.ID]
+		progress := MissionProgress{
+			Type:        strings.TrimPrefix(definition.Type, "EAresMissionType::"),
+			Description: definition.Title,
+			Goal:        definition.ProgressToComplete,
+			XP:          definition.XpGrant,
+			Complete:    mission.Complete,
+			ExpiresAt:   mission.ExpirationTime,
+		}
+
+		if progress.Description == "" {
+			progress.Description = definition.DisplayName
+		}
+
+		// a mission can have several objectives, its progress and goal are their totals
+		goals, known := map[string]int{}, []string{}
+		for _, objective := range definition.Objectives {
+			goals[objective.ObjectiveUuid] = objective.Value
+			known = append(known, objective.ObjectiveUuid)
+		}
+
+		goal, directives := 0, []string{}
+		for _, objectiveId := range objectiveOrder(mission.Objectives, known) {
+			progress.Progress += mission.Objectives[objectiveId]
+			goal += goals[objectiveId]
+			if directive := objectives[objectiveId].Directive; directive != "" {
+				directives = append(directives, directive)
+			}
+		}
+		if goal > 0 {
+			progress.Goal = goal
+		}
+		if len(directives) > 0 {
+			progress.Description = strings.Join(directives, ", ")
+		}
+
+		overview.Missions = append(overview.Missions, progress)
+	}
+
+	sort.SliceStable(overview.Missions, func(i, j int) bool {
+		a, b := overview.Missions[i], overview.Missions[j]
+		if missionTypeOrder[a.Type] != missionTypeOrder[b.Type] {
+			return missionTypeOrder[a.Type] < missionTypeOrder[b.Type]
+		}
+		return a.ExpiresAt.Before(b.ExpiresAt)
+	})
+
+	return overview, nil
+}
+
+// objectiveOrder returns the ids of the objectives in the order the mission lists them, then
+// any it doesn't know about sorted by id
+func objectiveOrder(progress map[string]int, known []string) []string {
+	ids, seen := []string{}, map[string]bool{}
+	for _, id := range known {
+		if _, ok := progress[id]; ok && !seen[id] {
+			ids = append(ids, id)
+			seen[id] = true
+		}
+	}
+
+	unknown := []string{}
+	for id := range progress {
+		if !seen[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+
+	return append(ids, unknown...)
+}
+
+func GetMissions(c *core.Client) error {
+	overview, err := GetMissionProgress(c)
+	if err != nil {
+		return err
+	}
+
+	PrintMissions(overview)
+	return nil
+}
+
+func PrintMissions(overview *MissionsOverview) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintln(w, "🎯 Missions 🎯")
+	fmt.Fprintln(w, "Type\tMission\tProgress\tXP\tExpires")
+	for _, mission := range overview.Missions {
+		progress := fmt.Sprintf("%d/%d", mission.Progress, mission.Goal)
+		if mission.Complete {
+			progress = "✅ done"
+		}
+
+		expires := "-"
+		if !mission.ExpiresAt.IsZero() {
+			expires = fmt.Sprintf("in %s (%s)", store.FormatCountdown(time.Until(mission.ExpiresAt)), mission.ExpiresAt.Local().Format("Mon 02 Jan 15:04"))
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", mission.Type, mission.Description, progress, mission.XP, expires)
+	}
+	w.Flush()
+
+	if !overview.WeeklyRefillTime.IsZero() {
+		fmt.Printf("New weekly missions in %s\n", store.FormatCountdown(time.Until(overview.WeeklyRefillTime)))
+	}
+}
@@ -1,14 +1,41 @@
 package player
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
 
 	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/i18n"
 )
 
 const (
-	PlayerMMRUrl = "https://pd.%s.a.pvp.net/mmr/v1/players/%s"
+	PlayerMMRUrl     = "https://pd.%s.a.pvp.net/mmr/v1/players/%s"
+	CompetitiveQueue = "competitive"
+
+	mmrCacheTTL = 5 * time.Minute
+	// riot rate limits the mmr endpoint, so don't ask for a whole lobby at once
+	mmrConcurrency = 4
+)
+
+type MMRResult struct {
+	MMR *PlayerMMRResponse
+	Err error
+}
+
+type cachedMMR struct {
+	mmr       *PlayerMMRResponse
+	fetchedAt time.Time
+}
+
+var (
+	mmrCacheMu sync.Mutex
+	mmrCache   = map[string]cachedMMR{}
 )
 
 type PlayerMMRResponse struct {
@@ -58,29 +85,80 @@ type LatestCompetitiveUpdate struct {
 	AFKPenalty                   int    `json:"AFKPenalty"`
 }
 
-func GetPlayerMMR(c *core.Client) error {
-	url := fmt.Sprintf(PlayerMMRUrl, c.Region, c.AuthData.UserId)
-	req, err := c.RequestWithAuth("GET", url, nil)
+func FetchPlayerMMR(c *core.Client, puuid string) (*PlayerMMRResponse, error) {
+	url := fmt.Sprintf(PlayerMMRUrl, c.Shard(), puuid)
+	req, err := c.RequestWithClientInfo("GET", url, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	clientVersion, err := core.GetClientVersion()
 
-	if err != nil {
-		return err
+	playerMMRBody := new(PlayerMMRResponse)
+	if err = c.DoJSON(req, playerMMRBody); err != nil {
+		return nil, err
 	}
 
-	req.Header.Add("X-Riot-ClientPlatform", "ew0KCSJwbGF0Zm9ybVR5cGUiOiAiUEMiLA0KCSJwbGF0Zm9ybU9TIjogIldpbmRvd3MiLA0KCSJwbGF0Zm9ybU9TVmVyc2lvbiI6ICIxMC4wLjE5MDQyLjEuMjU2LjY0Yml0IiwNCgkicGxhdGZvcm1DaGlwc2V0IjogIlVua25vd24iDQp9")
-	req.Header.Add("X-Riot-ClientVersion", *clientVersion)
+	return playerMMRBody, nil
+}
+
+// MMRFor returns the MMR of any player, cached for a few minutes
+func MMRFor(c *core.Client, puuid string) (*PlayerMMRResponse, error) {
+	mmrCacheMu.Lock()
+	cached, ok := mmrCache[puuid]
+	mmrCacheMu.Unlock()
+
+	if ok && time.Since(cached.fetchedAt) < mmrCacheTTL {
+		return cached.mmr, nil
+	}
 
-	res, err := c.HttpClient.Do(req)
+	mmr, err := FetchPlayerMMR(c, puuid)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	defer res.Body.Close()
-	playerMMRBody := new(PlayerMMRResponse)
-	if err = json.NewDecoder(res.Body).Decode(&playerMMRBody); err != nil {
+	mmrCacheMu.Lock()
+	mmrCache[puuid] = cachedMMR{mmr: mmr, fetchedAt: time.Now()}
+	mmrCacheMu.Unlock()
+
+	return mmr, nil
+}
+
+// MMRForAll looks up several players at once, with a few requests in flight at a time
+// and cached players not requested again
+func MMRForAll(c *core.Client, puuids []string) map[string]MMRResult {
+	results := map[string]MMRResult{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, mmrConcurrency)
+
+	for _, puuid := range puuids {
+		mu.Lock()
+		_, seen := results[puuid]
+		results[puuid] = MMRResult{}
+		mu.Unlock()
+		if seen {
+			continue
+		}
+
+		wg.Add(1)
+		go func(puuid string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			mmr, err := MMRFor(c, puuid)
+			mu.Lock()
+			results[puuid] = MMRResult{MMR: mmr, Err: err}
+			mu.Unlock()
+		}(puuid)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func GetPlayerMMR(c *core.Client) error {
+	playerMMRBody, err := MMRFor(c, c.AuthData.UserId)
+	if err != nil {
 		return err
 	}
 
@@ -89,6 +167,61 @@ func GetPlayerMMR(c *core.Client) error {
 	return nil
 }
 
+// CurrentTier returns the competitive tier and RR for the given season, falling back
+// to the latest competitive update when the season has no data
+func (p *PlayerMMRResponse) CurrentTier(seasonId string) (int, int) {
+	if info, ok := p.QueueSkills[CompetitiveQueue].SeasonalInfoBySeasonID[seasonId]; ok && info.CompetitiveTier > 0 {
+		return info.CompetitiveTier, info.RankedRating
+	}
+
+	return p.LatestCompetitiveUpdate.TierAfterUpdate, p.LatestCompetitiveUpdate.RankedRatingAfterUpdate
+}
+
+// PeakTier returns the highest competitive tier reached in any act, as a tier of the current
+// tier set, and the first act it was reached in
+func (p *PlayerMMRResponse) PeakTier(tiers *ActTiers) (int, string) {
+	seasons := p.QueueSkills[CompetitiveQueue].SeasonalInfoBySeasonID
+
+	// acts riot knows in order, then any others so none is left out
+	acts, known := []string{}, map[string]bool{}
+	for _, act := range tiers.Acts {
+		if _, ok := seasons[act]; ok {
+			acts = append(acts, act)
+			known[act] = true
+		}
+	}
+	unknown := []string{}
+	for seasonId := range seasons {
+		if !known[seasonId] {
+			unknown = append(unknown, seasonId)
+		}
+	}
+	sort.Strings(unknown)
+	acts = append(acts, unknown...)
+
+	peakTier, peakSeason := 0, ""
+	for _, seasonId := range acts {
+		info := seasons[seasonId]
+		reached := []int{info.CompetitiveTier}
+		for tier := range info.WinsByTier {
+			if t, err := strconv.Atoi(tier); err == nil {
+				reached = append(reached, t)
+			}
+		}
+
+		for _, tier := range reached {
+			if tier <= 0 {
+				continue
+			}
+			if normalized := tiers.Normalize(seasonId, tier); normalized > peakTier {
+				peakTier, peakSeason = normalized, seasonId
+			}
+		}
+	}
+
+	return peakTier, peakSeason
+}
+
 func PrintMMR(p *PlayerMMRResponse) error {
 	tierMap, err := core.GetCompetitiveTiers()
 	if err != nil {
@@ -98,7 +231,146 @@ func PrintMMR(p *PlayerMMRResponse) error {
 	currentRank := tierMap[p.LatestCompetitiveUpdate.TierAfterUpdate]
 	rr := p.LatestCompetitiveUpdate.RankedRatingAfterUpdate
 
-	fmt.Printf("Your current rank: %s - %d/100 RR\n", currentRank, rr)
+	fmt.Println(i18n.Tf("Your current rank: %s - %d/100 RR", currentRank, rr))
 
 	return nil
 }
+
+type PlayerRank struct {
+	RiotId       string
+	Tier         int
+	TierName     string
+	RR           int
+	PeakTierName string
+	PeakAct      string
+	Wins         int
+	Games        int
+	Err          error `json:"-"`
+}
+
+// GetPlayerRanks looks up the current and peak rank of players by riot id
+func GetPlayerRanks(c *core.Client, riotIds []string) ([]PlayerRank, error) {
+	tiers, err := GetActTiers(c)
+	if err != nil {
+		return nil, err
+	}
+
+	act, err := CurrentAct(c)
+	if err != nil {
+		return nil, err
+	}
+
+	actNames, err := ActNames(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ranks := make([]PlayerRank, len(riotIds))
+	puuids := make([]string, len(riotIds))
+	byPuuid := []string{}
+	for i, riotId := range riotIds {
+		ranks[i].RiotId = riotId
+		puuids[i], ranks[i].Err = ResolveRiotId(c, riotId)
+		if IsPuuid(riotId) {
+			byPuuid = append(byPuuid, puuids[i])
+		}
+	}
+
+	// show names rather than puuids for players looked up by puuid
+	if len(byPuuid) > 0 {
+		if names, err := Names(c).Resolve(byPuuid); err == nil {
+			for i, riotId := range riotIds {
+				if entry, ok := names[puuids[i]]; ok && IsPuuid(riotId) && entry.GameName != "" {
+					ranks[i].RiotId = entry.RiotId()
+				}
+			}
+		}
+	}
+
+	lookups := []string{}
+	for i, puuid := range puuids {
+		if ranks[i].Err == nil {
+			lookups = append(lookups, puuid)
+		}
+	}
+
+	results := MMRForAll(c, lookups)
+	for i, puuid := range puuids {
+		if ranks[i].Err != nil {
+			continue
+		}
+
+		result := results[puuid]
+		if result.Err != nil {
+			ranks[i].Err = result.Err
+			continue
+		}
+
+		ranks[i].fill(result.MMR, act.ID, tiers, actNames)
+	}
+
+	return ranks, nil
+}
+
+// RankFor returns the current and peak rank of a player by puuid
+func RankFor(c *core.Client, puuid string) (*PlayerRank, error) {
+	tiers, err := GetActTiers(c)
+	if err != nil {
+		return nil, err
+	}
+
+	act, err := CurrentAct(c)
+	if err != nil {
+		return nil, err
+	}
+
+	actNames, err := ActNames(c)
+	if err != nil {
+		return nil, err
+	}
+
+	mmr, err := MMRFor(c, puuid)
+	if err != nil {
+		return nil, err
+	}
+
+	rank := &PlayerRank{RiotId: Names(c).Name(puuid)}
+	rank.fill(mmr, act.ID, tiers, actNames)
+	return rank, nil
+}
+
+func (rank *PlayerRank) fill(mmr *PlayerMMRResponse, actId string, tiers *ActTiers, actNames map[string]string) {
+	tier, rr := mmr.CurrentTier(actId)
+	peakTier, peakSeason := mmr.PeakTier(tiers)
+	info := mmr.QueueSkills[CompetitiveQueue].SeasonalInfoBySeasonID[actId]
+
+	rank.Tier = tier
+	rank.TierName = tiers.Current[tier]
+	rank.RR = rr
+	rank.PeakTierName = tiers.Current[peakTier]
+	rank.PeakAct = actNames[peakSeason]
+	rank.Wins, rank.Games = info.NumberOfWins, info.NumberOfGames
+}
+
+func PrintPlayerRanks(ranks []PlayerRank) {
+	FprintPlayerRanks(os.Stdout, ranks)
+}
+
+func FprintPlayerRanks(out io.Writer, ranks []PlayerRank) {
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintln(w, "Player\tRank\tRR\tPeak Rank\tWins/Games (act)")
+	for _, rank := range ranks {
+		if rank.Err != nil {
+			fmt.Fprintf(w, "%s\tlookup failed: %s\t\t\t\n", rank.RiotId, rank.Err)
+			continue
+		}
+
+		peak := rank.PeakTierName
+		if rank.PeakAct != "" {
+			peak = fmt.Sprintf("%s (%s)", rank.PeakTierName, rank.PeakAct)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%d/%d\n", rank.RiotId, rank.TierName, rank.RR, peak, rank.Wins, rank.Games)
+	}
+	w.Flush()
+}
@@ -0,0 +1,147 @@
+package player
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	NameServiceUrl = "https://pd.%s.a.pvp.net/name-service/v2/players"
+
+	DefaultNameTTL       = 30 * time.Minute
+	nameServiceBatchSize = 100
+)
+
+type NameServiceEntry struct {
+	DisplayName string `json:"DisplayName"`
+	Subject     string `json:"Subject"`
+	GameName    string `json:"GameName"`
+	TagLine     string `json:"TagLine"`
+}
+
+func (n NameServiceEntry) RiotId() string {
+	if n.GameName == "" {
+		return "(unknown)"
+	}
+
+	return fmt.Sprintf("%s#%s", n.GameName, n.TagLine)
+}
+
+func FetchPlayerNames(c *core.Client, puuids []string) (map[string]NameServiceEntry, error) {
+	body, err := json.Marshal(puuids)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf(NameServiceUrl, c.Shard())
+	req, err := c.RequestWithClientInfo("PUT", url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []NameServiceEntry
+	if err = c.DoJSON(req, &entries); err != nil {
+		return nil, err
+	}
+
+	names := map[string]NameServiceEntry{}
+	for _, entry := range entries {
+		names[entry.Subject] = entry
+		if entry.GameName != "" {
+			rememberRiotId(entry.RiotId(), entry.Subject)
+		}
+	}
+
+	return names, nil
+}
+
+// NameResolver turns puuids into riot ids, batching name service calls and caching results
+type NameResolver struct {
+	client *core.Client
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	names map[string]cachedName
+}
+
+type cachedName struct {
+	entry     NameServiceEntry
+	fetchedAt time.Time
+}
+
+var (
+	resolversMu sync.Mutex
+	resolvers   = map[*core.Client]*NameResolver{}
+)
+
+func NewNameResolver(c *core.Client, ttl time.Duration) *NameResolver {
+	return &NameResolver{client: c, ttl: ttl, names: map[string]cachedName{}}
+}
+
+// Names returns the resolver shared by everything rendering players for this client
+func Names(c *core.Client) *NameResolver {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+
+	if resolver, ok := resolvers[c]; ok {
+		return resolver
+	}
+
+	resolver := NewNameResolver(c, DefaultNameTTL)
+	resolvers[c] = resolver
+	return resolver
+}
+
+// Resolve returns the name service entries for the puuids, only requesting the ones
+// that aren't cached or have expired
+func (r *NameResolver) Resolve(puuids []string) (map[string]NameServiceEntry, error) {
+	entries := map[string]NameServiceEntry{}
+	missing := []string{}
+
+	r.mu.Lock()
+	for _, puuid := range puuids {
+		if cached, ok := r.names[puuid]; ok && time.Since(cached.fetchedAt) < r.ttl {
+			entries[puuid] = cached.entry
+		} else if _, queued := entries[puuid]; !queued {
+			entries[puuid] = NameServiceEntry{Subject: puuid}
+			missing = append(missing, puuid)
+		}
+	}
+	r.mu.Unlock()
+
+	for start := 0; start < len(missing); start += nameServiceBatchSize {
+		end := start + nameServiceBatchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+
+		fetched, err := FetchPlayerNames(r.client, missing[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		r.mu.Lock()
+		for puuid, entry := range fetched {
+			r.names[puuid] = cachedName{entry: entry, fetchedAt: time.Now()}
+			entries[puuid] = entry
+		}
+		r.mu.Unlock()
+	}
+
+	return entries, nil
+}
+
+// Name returns the riot id of a single player, or "(unknown)" if it can't be resolved
+func (r *NameResolver) Name(puuid string) string {
+	entries, err := r.Resolve([]string{puuid})
+	if err != nil {
+		return NameServiceEntry{}.RiotId()
+	}
+
+	return entries[puuid].RiotId()
+}
@@ -0,0 +1,286 @@
+package player
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	PartyPlayerUrl        = "https://glz-%s-1.%s.a.pvp.net/parties/v1/players/%s"
+	PartyUrl              = "https://glz-%s-1.%s.a.pvp.net/parties/v1/parties/%s"
+	PartyInviteUrl        = "https://glz-%s-1.%s.a.pvp.net/parties/v1/parties/%s/invites/name/%s/tag/%s"
+	PartyQueueUrl         = "https://glz-%s-1.%s.a.pvp.net/parties/v1/parties/%s/queue"
+	PartyAccessibilityUrl = "https://glz-%s-1.%s.a.pvp.net/parties/v1/parties/%s/accessibility"
+
+	PartyOpen   = "OPEN"
+	PartyClosed = "CLOSED"
+)
+
+type PartyPlayerResponse struct {
+	Subject        string `json:"Subject"`
+	Version        int64  `json:"Version"`
+	CurrentPartyID string `json:"CurrentPartyID"`
+}
+
+type Party struct {
+	ID              string        `json:"ID"`
+	MUCName         string        `json:"MUCName"`
+	VoiceRoomID     string        `json:"VoiceRoomID"`
+	Version         int64         `json:"Version"`
+	Members         []PartyMember `json:"Members"`
+	State           string        `json:"State"`
+	PreviousState   string        `json:"PreviousState"`
+	Accessibility   string        `json:"Accessibility"`
+	MatchmakingData struct {
+		QueueID string `json:"QueueID"`
+	} `json:"MatchmakingData"`
+	EligibleQueues []string `json:"EligibleQueues"`
+	QueueEntryTime string   `json:"QueueEntryTime"`
+}
+
+type PartyMember struct {
+	Subject         string         `json:"Subject"`
+	CompetitiveTier int            `json:"CompetitiveTier"`
+	PlayerIdentity  PlayerIdentity `json:"PlayerIdentity"`
+	IsOwner         bool           `json:"IsOwner"`
+	IsReady         bool           `json:"IsReady"`
+	IsModerator     bool           `json:"IsModerator"`
+}
+
+type PartyMemberView struct {
+	Subject  string
+	Name     string
+	IsSelf   bool
+	IsOwner  bool
+	IsReady  bool
+	Level    string
+	TierName string
+	RR       int
+}
+
+type PartyOverview struct {
+	ID            string
+	Queue         string
+	Accessibility string
+	State         string
+	Members       []PartyMemberView
+}
+
+func FetchPartyPlayer(c *core.Client) (*PartyPlayerResponse, error) {
+	url := fmt.Sprintf(PartyPlayerUrl, c.Region, c.Shard(), c.AuthData.UserId)
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(PartyPlayerResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func FetchParty(c *core.Client, partyId string) (*Party, error) {
+	url := fmt.Sprintf(PartyUrl, c.Region, c.Shard(), partyId)
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(Party)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// CurrentParty returns the party the player is in, the game always keeps players in one
+func CurrentParty(c *core.Client) (*Party, error) {
+	partyPlayer, err := FetchPartyPlayer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return FetchParty(c, partyPlayer.CurrentPartyID)
+}
+
+func (p *Party) post(c *core.Client, url string, body any) error {
+	data := []byte{}
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := c.RequestWithClientInfo("POST", url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	return c.DoJSON(req, nil)
+}
+
+func (p *Party) Invite(c *core.Client, riotId string) error {
+	gameName, tagLine, err := ParseRiotId(riotId)
+	if err != nil {
+		return err
+	}
+
+	return p.post(c, fmt.Sprintf(PartyInviteUrl, c.Region, c.Shard(), p.ID, url.PathEscape(gameName), url.PathEscape(tagLine)), nil)
+}
+
+// Kick removes a member, found by riot id or puuid, from the party
+func (p *Party) Kick(c *core.Client, member string) error {
+	puuid := ""
+	names, err := Names(c).Resolve(p.MemberIds())
+	if err != nil {
+		return err
+	}
+
+	for _, m := range p.Members {
+		if m.Subject == member || strings.EqualFold(names[m.Subject].RiotId(), member) {
+			puuid = m.Subject
+		}
+	}
+
+	if puuid == "" {
+		return fmt.Errorf("%s is not in your party", member)
+	}
+
+	req, err := c.RequestWithClientInfo("DELETE", fmt.Sprintf(PartyPlayerUrl, c.Region, c.Shard(), puuid), nil)
+	if err != nil {
+		return err
+	}
+
+	return c.DoJSON(req, nil)
+}
+
+func (p *Party) SetQueue(c *core.Client, queueId string) error {
+	eligible := false
+	for _, queue := range p.EligibleQueues {
+		eligible = eligible || queue == queueId
+	}
+
+	if !eligible {
+		return fmt.Errorf("party can't queue %s, eligible queues are: %s", queueId, strings.Join(p.EligibleQueues, ", "))
+	}
+
+	return p.post(c, fmt.Sprintf(PartyQueueUrl, c.Region, c.Shard(), p.ID), map[string]string{"queueId": queueId})
+}
+
+func (p *Party) SetOpen(c *core.Client, open bool) error {
+	accessibility := PartyClosed
+	if open {
+		accessibility = PartyOpen
+	}
+
+	return p.post(c, fmt.Sprintf(PartyAccessibilityUrl, c.Region, c.Shard(), p.ID), map[string]string{"accessibility": accessibility})
+}
+
+func (p *Party) MemberIds() []string {
+	ids := []string{}
+	for _, member := range p.Members {
+		ids = append(ids, member.Subject)
+	}
+
+	return ids
+}
+
+func GetPartyOverview(c *core.Client) (*PartyOverview, error) {
+	party, err := CurrentParty(c)
+	if err != nil {
+		return nil, err
+	}
+
+	tierMap, err := core.GetCompetitiveTiers()
+	if err != nil {
+		return nil, err
+	}
+
+	act, err := CurrentAct(c)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := Names(c).Resolve(party.MemberIds())
+	if err != nil {
+		return nil, err
+	}
+	mmrs := MMRForAll(c, party.MemberIds())
+
+	overview := &PartyOverview{
+		ID:            party.ID,
+		Queue:         QueueName(party.MatchmakingData.QueueID),
+		Accessibility: party.Accessibility,
+		State:         party.State,
+	}
+
+	for _, member := range party.Members {
+		view := PartyMemberView{
+			Subject:  member.Subject,
+			Name:     names[member.Subject].RiotId(),
+			IsSelf:   member.Subject == c.AuthData.UserId,
+			IsOwner:  member.IsOwner,
+			IsReady:  member.IsReady,
+			Level:    member.PlayerIdentity.Level(),
+			TierName: tierMap[member.CompetitiveTier],
+		}
+
+		if result := mmrs[member.Subject]; result.Err == nil {
+			tier, rr := result.MMR.CurrentTier(act.ID)
+			view.TierName, view.RR = tierMap[tier], rr
+		}
+
+		overview.Members = append(overview.Members, view)
+	}
+
+	return overview, nil
+}
+
+func GetParty(c *core.Client) error {
+	overview, err := GetPartyOverview(c)
+	if err != nil {
+		return err
+	}
+
+	PrintParty(overview)
+	return nil
+}
+
+func PrintParty(party *PartyOverview) {
+	accessibility := "🔒 Closed"
+	if party.Accessibility == PartyOpen {
+		accessibility = "🔓 Open"
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintf(w, "👥 Party - %s - %s - %d/5 👥\n", party.Queue, accessibility, len(party.Members))
+	fmt.Fprintln(w, "Player\tRank\tRR\tLevel\tReady")
+	for _, member := range party.Members {
+		name := member.Name
+		if member.IsOwner {
+			name = "👑 " + name
+		}
+		if member.IsSelf {
+			name += " (you)"
+		}
+
+		ready := "❌"
+		if member.IsReady {
+			ready = "✅"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", name, member.TierName, member.RR, member.Level, ready)
+	}
+	w.Flush()
+}
@@ -0,0 +1,245 @@
+package player
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/store"
+)
+
+const (
+	PenaltiesUrl = "https://pd.%s.a.pvp.net/restrictions/v3/penalties"
+
+	RestrictionQueue      = "Queue restriction"
+	RestrictionRanked     = "Ranked ban"
+	RestrictionQueueDelay = "Queue delay"
+	RestrictionLeaver     = "Leaver penalty"
+	RestrictionTextMute   = "Text chat ban"
+	RestrictionVoiceMute  = "Voice chat ban"
+	RestrictionGameBan    = "Game ban"
+	RestrictionRRPenalty  = "RR penalty"
+	RestrictionXPPenalty  = "XP penalty"
+	RestrictionWarning    = "Warning"
+)
+
+// effect names riot uses that map straight to a restriction kind, queue restrictions are
+// handled separately since they can be ranked bans
+var PenaltyEffectKinds = map[string]string{
+	"QueueDelayEffect":           RestrictionQueueDelay,
+	"LeaverPenaltyEffect":        RestrictionLeaver,
+	"TextChatMuteEffect":         RestrictionTextMute,
+	"TextChatRestrictionEffect":  RestrictionTextMute,
+	"VoiceChatMuteEffect":        RestrictionVoiceMute,
+	"VoiceChatRestrictionEffect": RestrictionVoiceMute,
+	"GameBanEffect":              RestrictionGameBan,
+	"RiotRestrictionEffect":      RestrictionGameBan,
+	"RankedRatingPenaltyEffect":  RestrictionRRPenalty,
+	"XPModificationEffect":       RestrictionXPPenalty,
+	"WarningEffect":              RestrictionWarning,
+	"PBEWarningEffect":           RestrictionWarning,
+}
+
+type PenaltiesResponse struct {
+	Subject   string    `json:"Subject"`
+	Penalties []Penalty `json:"Penalties"`
+	Version   int64     `json:"Version"`
+}
+
+type Penalty struct {
+	ID                         string    `json:"ID"`
+	IssuingGameStartUnixMillis int64     `json:"IssuingGameStartUnixMillis"`
+	Expiry                     time.Time `json:"Expiry"`
+	GamesRemaining             int       `json:"GamesRemaining"`
+	ApplyToAllPlatformTypes    bool      `json:"ApplyToAllPlatformTypes"`
+	QueueRestrictionEffect     *struct {
+		QueueIDs []string `json:"QueueIDs"`
+	} `json:"QueueRestrictionEffect"`
+	QueueDelayEffect *struct {
+		QueueDelaySeconds int `json:"QueueDelaySeconds"`
+	} `json:"QueueDelayEffect"`
+	RankedRatingPenaltyEffect *struct {
+		RankedRatingPenalty int `json:"RankedRatingPenalty"`
+	} `json:"RankedRatingPenaltyEffect"`
+
+	// every effect riot sent, including the ones we don't know the shape of
+	Effects []string `json:"-"`
+}
+
+func (p *Penalty) UnmarshalJSON(data []byte) error {
+	type penalty Penalty
+	if err := json.Unmarshal(data, (*penalty)(p)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	p.Effects = nil
+	for name, value := range fields {
+		if strings.HasSuffix(name, "Effect") && string(value) != "null" {
+			p.Effects = append(p.Effects, name)
+		}
+	}
+	sort.Strings(p.Effects)
+
+	return nil
+}
+
+type Restriction struct {
+	Kind   string
+	Detail string
+	// zero when the restriction is lifted by playing games instead
+	Expiry         time.Time
+	GamesRemaining int
+	QueueIDs       []string
+}
+
+// Remaining describes how long until the restriction is lifted
+func (r Restriction) Remaining() string {
+	parts := []string{}
+	if !r.Expiry.IsZero() {
+		parts = append(parts, fmt.Sprintf("%s (%s)", store.FormatCountdown(time.Until(r.Expiry)), r.Expiry.Local().Format("Mon 02 Jan 15:04")))
+	}
+	if r.GamesRemaining > 0 {
+		parts = append(parts, fmt.Sprintf("%d games", r.GamesRemaining))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// BlocksQueue reports whether the restriction keeps the player out of the queue
+func (r Restriction) BlocksQueue(queueId string) bool {
+	switch r.Kind {
+	case RestrictionGameBan:
+		return true
+	case RestrictionQueue, RestrictionRanked:
+		if len(r.QueueIDs) == 0 {
+			return true
+		}
+		for _, id := range r.QueueIDs {
+			if id == queueId {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func FetchPenalties(c *core.Client) (*PenaltiesResponse, error) {
+	url := fmt.Sprintf(PenaltiesUrl, c.Shard())
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(PenaltiesResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// GetRestrictions returns the account's active restrictions, one per penalty effect
+func GetRestrictions(c *core.Client) ([]Restriction, error) {
+	penalties, err := FetchPenalties(c)
+	if err != nil {
+		return nil, err
+	}
+
+	restrictions := []Restriction{}
+	for _, penalty := range penalties.Penalties {
+		if !penalty.Expiry.IsZero() && penalty.Expiry.Before(time.Now()) && penalty.GamesRemaining <= 0 {
+			continue
+		}
+
+		for _, effect := range penalty.Effects {
+			restriction := Restriction{Expiry: penalty.Expiry, GamesRemaining: penalty.GamesRemaining}
+
+			switch {
+			case effect == "QueueRestrictionEffect":
+				restriction.Kind = RestrictionQueue
+				restriction.QueueIDs = penalty.QueueRestrictionEffect.QueueIDs
+				names := []string{}
+				for _, id := range restriction.QueueIDs {
+					names = append(names, QueueName(id))
+					if id == "competitive" {
+						restriction.Kind = RestrictionRanked
+					}
+				}
+				restriction.Detail = strings.Join(names, ", ")
+			case effect == "QueueDelayEffect" && penalty.QueueDelayEffect.QueueDelaySeconds > 0:
+				restriction.Kind = RestrictionQueueDelay
+				restriction.Detail = fmt.Sprintf("%s per queue", time.Duration(penalty.QueueDelayEffect.QueueDelaySeconds)*time.Second)
+			case effect == "RankedRatingPenaltyEffect" && penalty.RankedRatingPenaltyEffect.RankedRatingPenalty > 0:
+				restriction.Kind = RestrictionRRPenalty
+				restriction.Detail = fmt.Sprintf("-%d RR", penalty.RankedRatingPenaltyEffect.RankedRatingPenalty)
+			default:
+				kind, ok := PenaltyEffectKinds[effect]
+				if !ok {
+					kind, restriction.Detail = "Other", strings.TrimSuffix(effect, "Effect")
+				}
+				restriction.Kind = kind
+			}
+
+			restrictions = append(restrictions, restriction)
+		}
+	}
+
+	return restrictions, nil
+}
+
+// QueueBlockers returns the restrictions that keep the player out of a queue
+func QueueBlockers(restrictions []Restriction, queueId string) []Restriction {
+	blockers := []Restriction{}
+	for _, restriction := range restrictions {
+		if restriction.BlocksQueue(queueId) {
+			blockers = append(blockers, restriction)
+		}
+	}
+
+	return blockers
+}
+
+func GetStatus(c *core.Client) error {
+	restrictions, err := GetRestrictions(c)
+	if err != nil {
+		return err
+	}
+
+	PrintStatus(restrictions)
+	return nil
+}
+
+func PrintStatus(restrictions []Restriction) {
+	if len(restrictions) == 0 {
+		fmt.Println("✅ No active penalties or restrictions, you're free to queue ranked")
+		return
+	}
+
+	if blockers := QueueBlockers(restrictions, "competitive"); len(blockers) > 0 {
+		fmt.Printf("⛔ You can't queue ranked for %s\n", blockers[0].Remaining())
+	} else {
+		fmt.Println("✅ You can queue ranked")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintln(w, "🚫 Restrictions 🚫")
+	fmt.Fprintln(w, "Restriction\tDetails\tRemaining")
+	for _, restriction := range restrictions {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", restriction.Kind, restriction.Detail, restriction.Remaining())
+	}
+	w.Flush()
+}
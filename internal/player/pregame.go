@@ -0,0 +1,249 @@
+package player
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/goamaan/valocli/internal/content"
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	PregamePlayerUrl = "https://glz-%s-1.%s.a.pvp.net/pregame/v1/players/%s"
+	PregameMatchUrl  = "https://glz-%s-1.%s.a.pvp.net/pregame/v1/matches/%s"
+)
+
+type PregamePlayerResponse struct {
+	Subject string `json:"Subject"`
+	MatchID string `json:"MatchID"`
+	Version int64  `json:"Version"`
+}
+
+type PregameMatchResponse struct {
+	ID                   string        `json:"ID"`
+	Version              int64         `json:"Version"`
+	Teams                []PregameTeam `json:"Teams"`
+	AllyTeam             *PregameTeam  `json:"AllyTeam"`
+	EnemyTeam            *PregameTeam  `json:"EnemyTeam"`
+	PregameState         string        `json:"PregameState"`
+	MapID                string        `json:"MapID"`
+	GamePodID            string        `json:"GamePodID"`
+	Mode                 string        `json:"Mode"`
+	MUCName              string        `json:"MUCName"`
+	QueueID              string        `json:"QueueID"`
+	ProvisioningFlowID   string        `json:"ProvisioningFlowID"`
+	IsRanked             bool          `json:"IsRanked"`
+	PhaseTimeRemainingNS int64         `json:"PhaseTimeRemainingNS"`
+	StepTimeRemainingNS  int64         `json:"StepTimeRemainingNS"`
+}
+
+type PregameTeam struct {
+	TeamID  string          `json:"TeamID"`
+	Players []PregamePlayer `json:"Players"`
+}
+
+type PregamePlayer struct {
+	Subject                 string            `json:"Subject"`
+	CharacterID             string            `json:"CharacterID"`
+	CharacterSelectionState string            `json:"CharacterSelectionState"`
+	PregamePlayerState      string            `json:"PregamePlayerState"`
+	CompetitiveTier         int               `json:"CompetitiveTier"`
+	PlayerIdentity          PlayerIdentity    `json:"PlayerIdentity"`
+	SeasonalBadgeInfo       SeasonalBadgeInfo `json:"SeasonalBadgeInfo"`
+	IsCaptain               bool              `json:"IsCaptain"`
+}
+
+type PregameAlly struct {
+	Subject    string
+	Name       string
+	IsSelf     bool
+	Agent      string
+	Selection  string
+	Level      string
+	Tier       int
+	RR         int
+	TierName   string
+	MMRFailure error
+}
+
+type PregameOverview struct {
+	MatchID       string
+	Map           string
+	Queue         string
+	TimeRemaining time.Duration
+	Allies        []PregameAlly
+}
+
+func FetchPregamePlayer(c *core.Client) (*PregamePlayerResponse, error) {
+	url := fmt.Sprintf(PregamePlayerUrl, c.Region, c.Shard(), c.AuthData.UserId)
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(PregamePlayerResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func FetchPregameMatch(c *core.Client, matchId string) (*PregameMatchResponse, error) {
+	url := fmt.Sprintf(PregameMatchUrl, c.Region, c.Shard(), matchId)
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(PregameMatchResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// InPregame reports whether the player is currently in agent select
+func InPregame(c *core.Client) (bool, error) {
+	_, err := FetchPregamePlayer(c)
+	if err == core.ErrorRiotNotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// GetPregameOverview returns nil when the player isn't in agent select
+func GetPregameOverview(c *core.Client) (*PregameOverview, error) {
+	pregamePlayer, err := FetchPregamePlayer(c)
+	if err == core.ErrorRiotNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := FetchPregameMatch(c, pregamePlayer.MatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	tierMap, err := core.GetCompetitiveTiers()
+	if err != nil {
+		return nil, err
+	}
+
+	mapName, err := content.MapName(match.MapID)
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &PregameOverview{
+		MatchID:       match.ID,
+		Map:           mapName,
+		Queue:         QueueName(match.QueueID),
+		TimeRemaining: time.Duration(match.PhaseTimeRemainingNS),
+	}
+
+	if match.AllyTeam == nil {
+		return overview, nil
+	}
+
+	puuids := []string{}
+	for _, p := range match.AllyTeam.Players {
+		puuids = append(puuids, p.Subject)
+	}
+	mmrs := MMRForAll(c, puuids)
+	names, err := Names(c).Resolve(puuids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range match.AllyTeam.Players {
+		agent, err := content.AgentName(p.CharacterID)
+		if err != nil {
+			return nil, err
+		}
+
+		ally := PregameAlly{
+			Subject:   p.Subject,
+			Name:      names[p.Subject].RiotId(),
+			IsSelf:    p.Subject == c.AuthData.UserId,
+			Agent:     agent,
+			Selection: p.CharacterSelectionState,
+			Level:     p.PlayerIdentity.Level(),
+			Tier:      p.CompetitiveTier,
+		}
+
+		if p.PlayerIdentity.Incognito && !ally.IsSelf {
+			ally.Name = "(hidden)"
+		}
+
+		// a missing rank shouldn't hide the rest of agent select
+		if result := mmrs[p.Subject]; result.Err != nil {
+			ally.MMRFailure = result.Err
+		} else {
+			ally.Tier, ally.RR = result.MMR.CurrentTier(p.SeasonalBadgeInfo.SeasonID)
+		}
+
+		ally.TierName = tierMap[ally.Tier]
+		overview.Allies = append(overview.Allies, ally)
+	}
+
+	return overview, nil
+}
+
+func GetPregame(c *core.Client) error {
+	overview, err := GetPregameOverview(c)
+	if err != nil {
+		return err
+	}
+
+	PrintPregame(overview)
+	return nil
+}
+
+func PrintPregame(overview *PregameOverview) {
+	if overview == nil {
+		fmt.Println("You are not in agent select")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintf(w, "🎯 Agent select - %s - %s 🎯\n", overview.Map, overview.Queue)
+	fmt.Fprintf(w, "⏳ %ds left\n", int(overview.TimeRemaining.Seconds()))
+	fmt.Fprintln(w, "Player\tAgent\tState\tLevel\tRank")
+	for _, ally := range overview.Allies {
+		player := ally.Name
+		if ally.IsSelf {
+			player += " (you)"
+		}
+
+		agent := ally.Agent
+		if agent == "" {
+			agent = "-"
+		}
+
+		rank := fmt.Sprintf("%s - %d RR", ally.TierName, ally.RR)
+		if ally.MMRFailure != nil {
+			rank = fmt.Sprintf("%s (rank lookup failed)", ally.TierName)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", player, agent, selectionState(ally.Selection), ally.Level, rank)
+	}
+	w.Flush()
+}
+
+func selectionState(state string) string {
+	switch state {
+	case "locked":
+		return "🔒 Locked"
+	case "selected":
+		return "Hovering"
+	default:
+		return "Picking"
+	}
+}
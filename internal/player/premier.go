@@ -0,0 +1,364 @@
+package player
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	PremierPlayerUrl    = "https://pd.%s.a.pvp.net/premier/v2/players/%s"
+	PremierRosterUrl    = "https://pd.%s.a.pvp.net/premier/v2/rosters/%s"
+	PremierSeasonsUrl   = "https://pd.%s.a.pvp.net/premier/v1/affinities/%s/premier-seasons?active=true"
+	PremierStandingsUrl = "https://pd.%s.a.pvp.net/premier/v1/affinities/%s/conferences/%s/divisions/%d/leaderboard"
+
+	PremierQueue = "premier"
+
+	premierResultCount = 10
+)
+
+type PremierPlayerResponse struct {
+	PUUID    string `json:"PUUID"`
+	RosterID string `json:"RosterID"`
+	Version  int64  `json:"Version"`
+}
+
+type PremierRoster struct {
+	RosterID   string `json:"RosterID"`
+	Name       string `json:"Name"`
+	Tag        string `json:"Tag"`
+	Owner      string `json:"Owner"`
+	Conference string `json:"Conference"`
+	Division   int    `json:"Division"`
+	Score      int    `json:"Score"`
+	Members    []struct {
+		Puuid     string    `json:"Puuid"`
+		CreatedAt time.Time `json:"CreatedAt"`
+	} `json:"Members"`
+}
+
+type PremierSeasonsResponse struct {
+	PremierSeasons []PremierSeason `json:"PremierSeasons"`
+}
+
+type PremierSeason struct {
+	ID                  string               `json:"ID"`
+	CompetitiveSeasonID string               `json:"CompetitiveSeasonID"`
+	StartTime           time.Time            `json:"StartTime"`
+	EndTime             time.Time            `json:"EndTime"`
+	ScheduledEvents     []PremierMatchWindow `json:"ScheduledEvents"`
+}
+
+// PremierMatchWindow is a window the team can queue a league match or play the playoffs in
+type PremierMatchWindow struct {
+	ID            string    `json:"ID"`
+	Type          string    `json:"Type"`
+	Conference    string    `json:"Conference"`
+	StartDateTime time.Time `json:"StartDateTime"`
+	EndDateTime   time.Time `json:"EndDateTime"`
+}
+
+type PremierStandingsResponse struct {
+	Rosters []PremierStanding `json:"Rosters"`
+}
+
+type PremierStanding struct {
+	RosterID string `json:"RosterID"`
+	Name     string `json:"Name"`
+	Tag      string `json:"Tag"`
+	Score    int    `json:"Score"`
+	Wins     int    `json:"Wins"`
+	Losses   int    `json:"Losses"`
+}
+
+type PremierMember struct {
+	Puuid    string
+	Name     string
+	IsOwner  bool
+	Wins     int
+	Games    int
+	JoinedAt time.Time
+}
+
+type PremierOverview struct {
+	Roster    *PremierRoster
+	Members   []PremierMember
+	Standings []PremierStanding
+	Schedule  []PremierMatchWindow
+	Results   []MatchSummary
+}
+
+func premierGet(c *core.Client, url string, out any) error {
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.DoJSON(req, out)
+}
+
+func FetchPremierPlayer(c *core.Client) (*PremierPlayerResponse, error) {
+	body := new(PremierPlayerResponse)
+	if err := premierGet(c, fmt.Sprintf(PremierPlayerUrl, c.Shard(), c.AuthData.UserId), body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func FetchPremierRoster(c *core.Client, rosterId string) (*PremierRoster, error) {
+	body := new(PremierRoster)
+	if err := premierGet(c, fmt.Sprintf(PremierRosterUrl, c.Shard(), rosterId), body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func FetchPremierSeasons(c *core.Client) (*PremierSeasonsResponse, error) {
+	body := new(PremierSeasonsResponse)
+	if err := premierGet(c, fmt.Sprintf(PremierSeasonsUrl, c.Shard(), c.Region), body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func FetchPremierStandings(c *core.Client, conference string, division int) (*PremierStandingsResponse, error) {
+	body := new(PremierStandingsResponse)
+	if err := premierGet(c, fmt.Sprintf(PremierStandingsUrl, c.Shard(), c.Region, conference, division), body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// PremierTeam returns the roster of the player's premier team
+func PremierTeam(c *core.Client) (*PremierRoster, error) {
+	premierPlayer, err := FetchPremierPlayer(c)
+	if err != nil {
+		return nil, err
+	}
+	if premierPlayer.RosterID == "" {
+		return nil, fmt.Errorf("you're not on a premier team")
+	}
+
+	return FetchPremierRoster(c, premierPlayer.RosterID)
+}
+
+// PremierSchedule returns the match windows of the team's conference that haven't ended yet
+func PremierSchedule(c *core.Client, conference string) ([]PremierMatchWindow, error) {
+	seasons, err := FetchPremierSeasons(c)
+	if err != nil {
+		return nil, err
+	}
+
+	windows := []PremierMatchWindow{}
+	for _, season := range seasons.PremierSeasons {
+		for _, window := range season.ScheduledEvents {
+			if window.EndDateTime.After(time.Now()) && (window.Conference == "" || window.Conference == conference) {
+				windows = append(windows, window)
+			}
+		}
+	}
+
+	sort.Slice(windows, func(i, j int) bool { return windows[i].StartDateTime.Before(windows[j].StartDateTime) })
+	return windows, nil
+}
+
+// GetPremierOverview collects the player's premier team, its division standings, upcoming
+// match windows and recent premier results
+func GetPremierOverview(c *core.Client) (*PremierOverview, error) {
+	roster, err := PremierTeam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	act, err := CurrentAct(c)
+	if err != nil {
+		return nil, err
+	}
+
+	puuids := []string{}
+	for _, member := range roster.Members {
+		puuids = append(puuids, member.Puuid)
+	}
+
+	names, err := Names(c).Resolve(puuids)
+	if err != nil {
+		return nil, err
+	}
+	mmrs := MMRForAll(c, puuids)
+
+	overview := &PremierOverview{Roster: roster}
+	for _, member := range roster.Members {
+		view := PremierMember{
+			Puuid:    member.Puuid,
+			Name:     names[member.Puuid].RiotId(),
+			IsOwner:  member.Puuid == roster.Owner,
+			JoinedAt: member.CreatedAt,
+		}
+
+		if result := mmrs[member.Puuid]; result.Err == nil {
+			info := result.MMR.QueueSkills[PremierQueue].SeasonalInfoBySeasonID[act.ID]
+			view.Wins, view.Games = info.NumberOfWins, info.NumberOfGames
+		}
+
+		overview.Members = append(overview.Members, view)
+	}
+
+	standings, err := FetchPremierStandings(c, roster.Conference, roster.Division)
+	if err != nil {
+		return nil, err
+	}
+	overview.Standings = standings.Rosters
+
+	if overview.Schedule, err = PremierSchedule(c, roster.Conference); err != nil {
+		return nil, err
+	}
+
+	if overview.Results, err = RecentMatches(c, c.AuthData.UserId, PremierQueue, premierResultCount); err != nil {
+		return nil, err
+	}
+
+	return overview, nil
+}
+
+func GetPremier(c *core.Client) error {
+	overview, err := GetPremierOverview(c)
+	if err != nil {
+		return err
+	}
+
+	PrintPremier(overview)
+	return nil
+}
+
+func PrintPremier(overview *PremierOverview) {
+	roster := overview.Roster
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintf(w, "🛡️ %s [%s] - %s - Division %d - %d points 🛡️\n", roster.Name, roster.Tag, roster.Conference, roster.Division, roster.Score)
+	fmt.Fprintln(w, "Player\tPremier wins\tGames this act\tJoined")
+	for _, member := range overview.Members {
+		name := member.Name
+		if member.IsOwner {
+			name = "👑 " + name
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", name, member.Wins, member.Games, member.JoinedAt.Local().Format("02 Jan 2006"))
+	}
+	w.Flush()
+
+	fmt.Println("★★★★★★★★★★★★★★★★")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintln(w, "📊 Division standings 📊")
+	fmt.Fprintln(w, "#\tTeam\tPoints\tW-L")
+	for i, standing := range overview.Standings {
+		name := fmt.Sprintf("%s [%s]", standing.Name, standing.Tag)
+		if standing.RosterID == roster.RosterID {
+			name = "⭐ " + name
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d-%d\n", i+1, name, standing.Score, standing.Wins, standing.Losses)
+	}
+	w.Flush()
+
+	fmt.Println("★★★★★★★★★★★★★★★★")
+	PrintPremierSchedule(overview.Schedule)
+
+	fmt.Println("★★★★★★★★★★★★★★★★")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintln(w, "⚔️ Recent premier matches ⚔️")
+	fmt.Fprintln(w, "Date\tMap\tResult\tScore\tAgent\tK/D/A")
+	for _, match := range overview.Results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", match.Start.Local().Format("Mon 02 Jan 15:04"), match.Map, match.Result, match.Score(), match.Agent, match.KDA())
+	}
+	w.Flush()
+}
+
+func PrintPremierSchedule(windows []PremierMatchWindow) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintln(w, "📅 Upcoming match windows 📅")
+	fmt.Fprintln(w, "Type\tStarts\tEnds")
+	for _, window := range windows {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", premierWindowName(window),
+			window.StartDateTime.Local().Format("Mon 02 Jan 15:04"), window.EndDateTime.Local().Format("Mon 02 Jan 15:04"))
+	}
+	w.Flush()
+}
+
+func premierWindowName(window PremierMatchWindow) string {
+	if window.Type == "" {
+		return "Match"
+	}
+
+	return strings.ToUpper(window.Type[:1]) + strings.ToLower(window.Type[1:])
+}
+
+// WritePremierCalendar writes the match windows as an iCalendar file that calendar apps can import
+func WritePremierCalendar(out io.Writer, team string, windows []PremierMatchWindow) error {
+	const stamp = "20060102T150405Z"
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//valocli//premier//EN",
+		"CALSCALE:GREGORIAN",
+	}
+	for _, window := range windows {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s@valocli", window.ID),
+			"DTSTAMP:"+time.Now().UTC().Format(stamp),
+			"DTSTART:"+window.StartDateTime.UTC().Format(stamp),
+			"DTEND:"+window.EndDateTime.UTC().Format(stamp),
+			fmt.Sprintf("SUMMARY:%s premier %s", icalEscape(team), strings.ToLower(premierWindowName(window))),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	// icalendar wants crlf line endings
+	_, err := io.WriteString(out, strings.Join(lines, "\r\n")+"\r\n")
+	return err
+}
+
+func icalEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// ExportPremierSchedule writes the team's upcoming match windows to an .ics file
+func ExportPremierSchedule(c *core.Client, path string) error {
+	roster, err := PremierTeam(c)
+	if err != nil {
+		return err
+	}
+
+	windows, err := PremierSchedule(c, roster.Conference)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err = WritePremierCalendar(file, roster.Name, windows); err != nil {
+		file.Close()
+		return err
+	}
+
+	// a failed close can mean the calendar never made it to disk
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d match windows to %s\n", len(windows), path)
+	return nil
+}
@@ -0,0 +1,293 @@
+package player
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/goamaan/valocli/internal/content"
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/store"
+)
+
+const (
+	presetExtension = ".json"
+
+	DefaultPlayerCardId = "9fb348bc-41a0-91ad-8a3e-818035c4e561"
+	NoPlayerTitleId     = "d13e579c-435e-44d4-cec2-6eae5a3c5ed4"
+)
+
+type LoadoutChange struct {
+	Slot string
+	From string
+	To   string
+}
+
+// MissingItemsError lists the preset items that are no longer owned
+type MissingItemsError struct {
+	Items []string
+}
+
+func (e *MissingItemsError) Error() string {
+	return fmt.Sprintf("preset contains items you don't own: %s", strings.Join(e.Items, ", "))
+}
+
+func presetPath(dir, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\:`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid preset name %q", name)
+	}
+
+	return filepath.Join(dir, name+presetExtension), nil
+}
+
+// SavePreset snapshots the current loadout into dir under the given name
+func SavePreset(c *core.Client, dir, name string) error {
+	path, err := presetPath(dir, name)
+	if err != nil {
+		return err
+	}
+
+	loadout, err := FetchPlayerLoadout(c, c.AuthData.UserId)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(loadout, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+func LoadPreset(dir, name string) (*PlayerLoadoutResponse, error) {
+	path, err := presetPath(dir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no preset named %q", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	loadout := new(PlayerLoadoutResponse)
+	if err = json.Unmarshal(data, loadout); err != nil {
+		return nil, err
+	}
+
+	return loadout, nil
+}
+
+func ListPresets(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), presetExtension) {
+			names = append(names, strings.TrimSuffix(entry.Name(), presetExtension))
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// DiffLoadouts lists every slot that differs between two loadouts, by display name
+func DiffLoadouts(from, to *PlayerLoadoutResponse) ([]LoadoutChange, error) {
+	fromView, err := ResolveLoadout(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toView, err := ResolveLoadout(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []LoadoutChange{}
+	change := func(slot, a, b string) {
+		if a != b {
+			changes = append(changes, LoadoutChange{Slot: slot, From: a, To: b})
+		}
+	}
+
+	// slots of both loadouts, in the order of the first and then any only the second has
+	fromWeapons, toWeapons := map[string]LoadoutWeapon{}, map[string]LoadoutWeapon{}
+	weapons := []LoadoutWeapon{}
+	for _, weapon := range fromView.Weapons {
+		fromWeapons[weapon.ID] = weapon
+		weapons = append(weapons, weapon)
+	}
+	for _, weapon := range toView.Weapons {
+		toWeapons[weapon.ID] = weapon
+		if _, ok := fromWeapons[weapon.ID]; !ok {
+			weapons = append(weapons, weapon)
+		}
+	}
+
+	for _, weapon := range weapons {
+		a, b := fromWeapons[weapon.ID], toWeapons[weapon.ID]
+		change(weapon.Weapon+" skin", a.SkinLevel, b.SkinLevel)
+		change(weapon.Weapon+" chroma", a.Chroma, b.Chroma)
+		change(weapon.Weapon+" buddy", a.Buddy, b.Buddy)
+	}
+
+	fromSprays, toSprays := map[string]LoadoutSprayView{}, map[string]LoadoutSprayView{}
+	sprays := []LoadoutSprayView{}
+	for _, spray := range fromView.Sprays {
+		fromSprays[spray.SlotID] = spray
+		sprays = append(sprays, spray)
+	}
+	for _, spray := range toView.Sprays {
+		toSprays[spray.SlotID] = spray
+		if _, ok := fromSprays[spray.SlotID]; !ok {
+			sprays = append(sprays, spray)
+		}
+	}
+
+	for _, spray := range sprays {
+		change(spray.Slot+" spray", fromSprays[spray.SlotID].Spray, toSprays[spray.SlotID].Spray)
+	}
+
+	change("Player card", fromView.PlayerCard, toView.PlayerCard)
+	change("Title", fromView.PlayerTitle, toView.PlayerTitle)
+	change("Level border", fromView.LevelBorder, toView.LevelBorder)
+
+	return changes, nil
+}
+
+// ValidateOwnership checks that every item in the loadout is still owned through entitlements.
+// Default skins, base chromas and the default card and title are free and always allowed.
+func ValidateOwnership(c *core.Client, loadout *PlayerLoadoutResponse) error {
+	owned := map[string]map[string]bool{}
+	for _, itemTypeId := range []string{store.SkinsId, store.SkinVariantsId, store.GunBuddiesId, store.SpraysId, store.CardsId, store.TitlesId} {
+		items, err := store.OwnedItems(c, itemTypeId)
+		if err != nil {
+			return err
+		}
+		owned[itemTypeId] = items
+	}
+
+	weapons, err := content.Weapons()
+	if err != nil {
+		return err
+	}
+
+	missing := []string{}
+	require := func(itemTypeId, itemId string) error {
+		if itemId == "" || owned[itemTypeId][itemId] {
+			return nil
+		}
+
+		name, _, err := itemName(itemTypeId, itemId)
+		if err != nil {
+			return err
+		}
+
+		missing = append(missing, name)
+		return nil
+	}
+
+	for _, gun := range loadout.Guns {
+		weapon := weapons[gun.ID]
+		if gun.SkinID != weapon.DefaultSkinUuid {
+			if err = require(store.SkinsId, gun.SkinLevelID); err != nil {
+				return err
+			}
+
+			skin := weapon.Skin(gun.SkinID)
+			if skin == nil || len(skin.Chromas) == 0 || skin.Chromas[0].UUID != gun.ChromaID {
+				if err = require(store.SkinVariantsId, gun.ChromaID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err = require(store.GunBuddiesId, gun.CharmLevelID); err != nil {
+			return err
+		}
+	}
+
+	for _, spray := range loadout.Sprays {
+		if err = require(store.SpraysId, spray.SprayID); err != nil {
+			return err
+		}
+	}
+
+	if loadout.Identity.PlayerCardID != DefaultPlayerCardId {
+		if err = require(store.CardsId, loadout.Identity.PlayerCardID); err != nil {
+			return err
+		}
+	}
+
+	if loadout.Identity.PlayerTitleID != NoPlayerTitleId {
+		if err = require(store.TitlesId, loadout.Identity.PlayerTitleID); err != nil {
+			return err
+		}
+	}
+
+	if len(missing) > 0 {
+		return &MissingItemsError{Items: missing}
+	}
+
+	return nil
+}
+
+// ApplyPreset equips a saved preset after checking that every item in it is still owned. Only
+// the cosmetics come from the preset, privacy settings like incognito stay as they are now
+func ApplyPreset(c *core.Client, dir, name string) error {
+	preset, err := LoadPreset(dir, name)
+	if err != nil {
+		return err
+	}
+
+	if err = ValidateOwnership(c, preset); err != nil {
+		return err
+	}
+
+	current, err := FetchPlayerLoadout(c, c.AuthData.UserId)
+	if err != nil {
+		return err
+	}
+
+	current.Guns = preset.Guns
+	current.Sprays = preset.Sprays
+	current.Identity.PlayerCardID = preset.Identity.PlayerCardID
+	current.Identity.PlayerTitleID = preset.Identity.PlayerTitleID
+	current.Identity.PreferredLevelBorderID = preset.Identity.PreferredLevelBorderID
+
+	_, err = PutPlayerLoadout(c, current)
+	return err
+}
+
+func PrintLoadoutChanges(changes []LoadoutChange) {
+	if len(changes) == 0 {
+		fmt.Println("Loadouts are identical")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintln(w, "Slot\tFrom\tTo")
+	for _, change := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", change.Slot, change.From, change.To)
+	}
+	w.Flush()
+}
@@ -0,0 +1,116 @@
+package player
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	LocalAliasLookupPath = "/player-account/aliases/v1/lookup?gameName=%s&tagLine=%s"
+	AliasLookupUrl       = "https://api.account.riotgames.com/aliases/v1/aliases?gameName=%s&tagLine=%s"
+)
+
+// AliasLookupEntry is an account with the riot id, the Riot Client nests the name under
+// alias while the account service sends it next to the puuid
+type AliasLookupEntry struct {
+	Active bool `json:"active"`
+	Alias  struct {
+		GameName string `json:"game_name"`
+		TagLine  string `json:"tag_line"`
+	} `json:"alias"`
+	GameName string `json:"game_name"`
+	TagLine  string `json:"tag_line"`
+	Puuid    string `json:"puuid"`
+}
+
+func (e AliasLookupEntry) RiotId() (string, string) {
+	if e.Alias.GameName != "" {
+		return e.Alias.GameName, e.Alias.TagLine
+	}
+
+	return e.GameName, e.TagLine
+}
+
+var puuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func IsPuuid(s string) bool {
+	return puuidPattern.MatchString(strings.TrimSpace(s))
+}
+
+var (
+	riotIdCacheMu sync.Mutex
+	riotIdCache   = map[string]string{}
+)
+
+func ParseRiotId(riotId string) (string, string, error) {
+	gameName, tagLine, ok := strings.Cut(strings.TrimSpace(riotId), "#")
+	if !ok || gameName == "" || tagLine == "" {
+		return "", "", core.ErrorRiotIdMalformed
+	}
+
+	return gameName, tagLine, nil
+}
+
+// rememberRiotId records puuids seen through the name service so they can be looked up by riot id later
+func rememberRiotId(riotId, puuid string) {
+	riotIdCacheMu.Lock()
+	riotIdCache[strings.ToLower(riotId)] = puuid
+	riotIdCacheMu.Unlock()
+}
+
+// ResolveRiotId turns a name#tag into a puuid, puuids are returned as they are. Unknown
+// riot ids are looked up through the running Riot Client, or riot's account service.
+func ResolveRiotId(c *core.Client, riotId string) (string, error) {
+	if IsPuuid(riotId) {
+		return strings.ToLower(strings.TrimSpace(riotId)), nil
+	}
+
+	gameName, tagLine, err := ParseRiotId(riotId)
+	if err != nil {
+		return "", err
+	}
+
+	riotIdCacheMu.Lock()
+	puuid, ok := riotIdCache[strings.ToLower(gameName+"#"+tagLine)]
+	riotIdCacheMu.Unlock()
+	if ok {
+		return puuid, nil
+	}
+
+	entries, err := lookupAlias(c, gameName, tagLine)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		name, tag := entry.RiotId()
+		if entry.Active && strings.EqualFold(name, gameName) && strings.EqualFold(tag, tagLine) {
+			rememberRiotId(gameName+"#"+tagLine, entry.Puuid)
+			return entry.Puuid, nil
+		}
+	}
+
+	return "", core.ErrorRiotIdNotFound
+}
+
+func lookupAlias(c *core.Client, gameName, tagLine string) ([]AliasLookupEntry, error) {
+	var entries []AliasLookupEntry
+	if c.IsLocal() {
+		path := fmt.Sprintf(LocalAliasLookupPath, url.QueryEscape(gameName), url.QueryEscape(tagLine))
+		err := c.GetLocal(path, &entries)
+		return entries, err
+	}
+
+	req, err := c.RequestWithAuth("GET", fmt.Sprintf(AliasLookupUrl, url.QueryEscape(gameName), url.QueryEscape(tagLine)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.DoJSON(req, &entries)
+	return entries, err
+}
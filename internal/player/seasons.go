@@ -0,0 +1,176 @@
+package player
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goamaan/valocli/internal/content"
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	ContentServiceUrl     = "https://shared.%s.a.pvp.net/content-service/v3/content"
+	CompetitiveSeasonsUrl = content.BaseUrl + "/seasons/competitive"
+	SeasonTypeAct         = "act"
+	SeasonTypeEpisode     = "episode"
+)
+
+type ContentServiceResponse struct {
+	DisabledIDs []string `json:"DisabledIDs"`
+	Seasons     []Season `json:"Seasons"`
+}
+
+type Season struct {
+	ID        string    `json:"ID"`
+	Name      string    `json:"Name"`
+	Type      string    `json:"Type"`
+	StartTime time.Time `json:"StartTime"`
+	EndTime   time.Time `json:"EndTime"`
+	IsActive  bool      `json:"IsActive"`
+}
+
+var (
+	contentServiceMu    sync.Mutex
+	contentServiceCache *ContentServiceResponse
+)
+
+// FetchContentService returns riot's season list, cached for the lifetime of the process
+func FetchContentService(c *core.Client) (*ContentServiceResponse, error) {
+	contentServiceMu.Lock()
+	defer contentServiceMu.Unlock()
+
+	if contentServiceCache != nil {
+		return contentServiceCache, nil
+	}
+
+	url := fmt.Sprintf(ContentServiceUrl, c.Shard())
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(ContentServiceResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	contentServiceCache = body
+	return body, nil
+}
+
+func CurrentAct(c *core.Client) (*Season, error) {
+	contentService, err := FetchContentService(c)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, season := range contentService.Seasons {
+		if season.IsActive && season.Type == SeasonTypeAct {
+			return &season, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no active act found")
+}
+
+// ActNames maps season ids to names like "EPISODE 7 // ACT 2"
+func ActNames(c *core.Client) (map[string]string, error) {
+	contentService, err := FetchContentService(c)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	episode := ""
+	for _, season := range contentService.Seasons {
+		switch season.Type {
+		case SeasonTypeEpisode:
+			episode = season.Name
+		case SeasonTypeAct:
+			names[season.ID] = season.Name
+			if episode != "" {
+				names[season.ID] = fmt.Sprintf("%s // %s", episode, season.Name)
+			}
+		}
+	}
+
+	return names, nil
+}
+
+type competitiveSeason struct {
+	SeasonUuid           string `json:"seasonUuid"`
+	CompetitiveTiersUuid string `json:"competitiveTiersUuid"`
+}
+
+// ActTiers names the tiers of every act, the same tier number meant another rank before
+// ascendant was added in episode 5 (24 was radiant, it's now immortal 1)
+type ActTiers struct {
+	// act ids, oldest first
+	Acts []string
+	// tier names of the current tier set
+	Current map[int]string
+
+	byAct map[string]map[int]string
+}
+
+func GetActTiers(c *core.Client) (*ActTiers, error) {
+	contentService, err := FetchContentService(c)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := core.GetCompetitiveTiers()
+	if err != nil {
+		return nil, err
+	}
+
+	sets, err := core.GetCompetitiveTierSets()
+	if err != nil {
+		return nil, err
+	}
+
+	var seasons []competitiveSeason
+	if err = content.Get(CompetitiveSeasonsUrl, &seasons); err != nil {
+		return nil, err
+	}
+
+	tiers := &ActTiers{Current: current, byAct: map[string]map[int]string{}}
+	for _, season := range seasons {
+		if set, ok := sets[season.CompetitiveTiersUuid]; ok {
+			tiers.byAct[season.SeasonUuid] = set
+		}
+	}
+
+	acts := []Season{}
+	for _, season := range contentService.Seasons {
+		if season.Type == SeasonTypeAct {
+			acts = append(acts, season)
+		}
+	}
+	sort.SliceStable(acts, func(i, j int) bool { return acts[i].StartTime.Before(acts[j].StartTime) })
+	for _, act := range acts {
+		tiers.Acts = append(tiers.Acts, act.ID)
+	}
+
+	return tiers, nil
+}
+
+// Normalize returns the tier of the current tier set with the name tier had in the act, acts
+// without a known tier set are taken to use the current one
+func (t *ActTiers) Normalize(actId string, tier int) int {
+	name := t.byAct[actId][tier]
+	if name == "" {
+		return tier
+	}
+
+	for currentTier, currentName := range t.Current {
+		if strings.EqualFold(currentName, name) {
+			return currentTier
+		}
+	}
+
+	return tier
+}
@@ -0,0 +1,165 @@
+package player
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/store"
+)
+
+const (
+	AccountXPUrl = "https://pd.%s.a.pvp.net/account-xp/v1/players/%s"
+	XPPerLevel   = 5000
+)
+
+var XPSourceNames = map[string]string{
+	"time-played":          "Match",
+	"match-win":            "Match win",
+	"first-win-of-the-day": "First win of the day",
+}
+
+type AccountXPResponse struct {
+	Version                   int              `json:"Version"`
+	Subject                   string           `json:"Subject"`
+	Progress                  XPProgress       `json:"Progress"`
+	History                   []XPHistoryEntry `json:"History"`
+	LastTimeGrantedFirstWin   time.Time        `json:"LastTimeGrantedFirstWin"`
+	NextTimeFirstWinAvailable time.Time        `json:"NextTimeFirstWinAvailable"`
+}
+
+type XPProgress struct {
+	Level int `json:"Level"`
+	XP    int `json:"XP"`
+}
+
+type XPHistoryEntry struct {
+	ID            string     `json:"ID"`
+	MatchStart    time.Time  `json:"MatchStart"`
+	StartProgress XPProgress `json:"StartProgress"`
+	EndProgress   XPProgress `json:"EndProgress"`
+	XPDelta       int        `json:"XPDelta"`
+	XPSources     []struct {
+		ID     string `json:"ID"`
+		Amount int    `json:"Amount"`
+	} `json:"XPSources"`
+}
+
+type XPDay struct {
+	Day          time.Time
+	Matches      int
+	BySource     map[string]int
+	Total        int
+	LevelsGained int
+}
+
+func XPSourceName(sourceId string) string {
+	if name, ok := XPSourceNames[sourceId]; ok {
+		return name
+	}
+
+	return strings.ReplaceAll(sourceId, "-", " ")
+}
+
+func FetchAccountXP(c *core.Client) (*AccountXPResponse, error) {
+	url := fmt.Sprintf(AccountXPUrl, c.Shard(), c.AuthData.UserId)
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(AccountXPResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// XPByDay sums the xp history per local calendar day, most recent day first
+func XPByDay(history []XPHistoryEntry) []XPDay {
+	days := map[time.Time]*XPDay{}
+	for _, entry := range history {
+		start := entry.MatchStart.Local()
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local)
+		if days[day] == nil {
+			days[day] = &XPDay{Day: day, BySource: map[string]int{}}
+		}
+
+		summary := days[day]
+		summary.Matches++
+		summary.Total += entry.XPDelta
+		summary.LevelsGained += entry.EndProgress.Level - entry.StartProgress.Level
+		for _, source := range entry.XPSources {
+			summary.BySource[source.ID] += source.Amount
+		}
+	}
+
+	summaries := []XPDay{}
+	for _, summary := range days {
+		summaries = append(summaries, *summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Day.After(summaries[j].Day)
+	})
+
+	return summaries
+}
+
+func GetAccountXP(c *core.Client) error {
+	xp, err := FetchAccountXP(c)
+	if err != nil {
+		return err
+	}
+
+	PrintAccountXP(xp)
+	return nil
+}
+
+func PrintAccountXP(xp *AccountXPResponse) {
+	fmt.Printf("⭐ Account level %d - %d/%d XP ⭐\n", xp.Progress.Level, xp.Progress.XP, XPPerLevel)
+	if !xp.NextTimeFirstWinAvailable.IsZero() {
+		if wait := time.Until(xp.NextTimeFirstWinAvailable); wait > 0 {
+			fmt.Printf("First win of the day bonus available again in %s\n", store.FormatCountdown(wait))
+		} else {
+			fmt.Println("First win of the day bonus is available")
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintln(w, "★★★★★★★★★★★★★★★★")
+	fmt.Fprintln(w, "Match start\tXP sources\tXP\tLevel")
+	for _, entry := range xp.History {
+		sources := []string{}
+		for _, source := range entry.XPSources {
+			sources = append(sources, fmt.Sprintf("%s +%d", XPSourceName(source.ID), source.Amount))
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t+%d\t%d → %d\n",
+			entry.MatchStart.Local().Format("Mon 02 Jan 15:04"), strings.Join(sources, ", "), entry.XPDelta,
+			entry.StartProgress.Level, entry.EndProgress.Level)
+	}
+
+	fmt.Fprintln(w, "★★★★★★★★★★★★★★★★")
+	fmt.Fprintln(w, "Day\tMatches\tXP sources\tXP\tLevels gained")
+	for _, day := range XPByDay(xp.History) {
+		sourceIds := []string{}
+		for sourceId := range day.BySource {
+			sourceIds = append(sourceIds, sourceId)
+		}
+		sort.Strings(sourceIds)
+
+		sources := []string{}
+		for _, sourceId := range sourceIds {
+			sources = append(sources, fmt.Sprintf("%s +%d", XPSourceName(sourceId), day.BySource[sourceId]))
+		}
+
+		fmt.Fprintf(w, "%s\t%d\t%s\t+%d\t%d\n", day.Day.Format("Mon 02 Jan"), day.Matches, strings.Join(sources, ", "), day.Total, day.LevelsGained)
+	}
+	w.Flush()
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"time"
+)
+
+// responses kept at most, every player or puuid asked about is another entry
+const maxCacheEntries = 256
+
+type cacheEntry struct {
+	value     any
+	fetchedAt time.Time
+}
+
+// fetchCall is a fetch in progress, requests for the same key wait for it instead of asking
+// riot again
+type fetchCall struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// cached returns the response for key if it's younger than the cache ttl, otherwise fetches
+// it once for however many requests want it at the same time. Errors aren't cached
+func (s *Server) cached(key string, fetch func() (any, error)) (any, error) {
+	s.cacheMu.Lock()
+	if entry, ok := s.cache[key]; ok && time.Since(entry.fetchedAt) < s.opts.CacheTTL {
+		s.cacheMu.Unlock()
+		return entry.value, nil
+	}
+
+	if call, ok := s.inflight[key]; ok {
+		s.cacheMu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+
+	call := &fetchCall{done: make(chan struct{})}
+	s.inflight[key] = call
+	s.cacheMu.Unlock()
+
+	// a panicking fetch still has to let the waiting requests go
+	defer func() {
+		s.cacheMu.Lock()
+		delete(s.inflight, key)
+		s.cacheMu.Unlock()
+		close(call.done)
+	}()
+
+	call.value, call.err = fetch()
+	if call.err == nil {
+		s.cacheMu.Lock()
+		s.remember(key, call.value)
+		s.cacheMu.Unlock()
+	}
+
+	return call.value, call.err
+}
+
+// remember adds a response, dropping expired ones and then the oldest beyond maxCacheEntries.
+// cacheMu must be held
+func (s *Server) remember(key string, value any) {
+	now := time.Now()
+	for k, entry := range s.cache {
+		if now.Sub(entry.fetchedAt) >= s.opts.CacheTTL {
+			delete(s.cache, k)
+		}
+	}
+
+	s.cache[key] = cacheEntry{value: value, fetchedAt: now}
+
+	for len(s.cache) > maxCacheEntries {
+		oldest := ""
+		for k, entry := range s.cache {
+			if oldest == "" || entry.fetchedAt.Before(s.cache[oldest].fetchedAt) {
+				oldest = k
+			}
+		}
+		delete(s.cache, oldest)
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+)
+
+func TestCachedFetchesOnceForConcurrentRequests(t *testing.T) {
+	s := New(core.New(nil), Options{MetricsOnly: true})
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	fetch := func() (any, error) {
+		fetches.Add(1)
+		<-release
+		return "store", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, err := s.cached("/store?", fetch); err != nil || value != "store" {
+				t.Errorf("got %v, %v", value, err)
+			}
+		}()
+	}
+
+	// let every request find the fetch in progress before it finishes
+	for {
+		s.cacheMu.Lock()
+		_, started := s.inflight["/store?"]
+		s.cacheMu.Unlock()
+		if started {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("riot was asked %d times, want once", n)
+	}
+	if value, _ := s.cached("/store?", func() (any, error) { return nil, errors.New("fetched again") }); value != "store" {
+		t.Errorf("cached response not reused: %v", value)
+	}
+}
+
+func TestCachedDoesNotKeepErrors(t *testing.T) {
+	s := New(core.New(nil), Options{MetricsOnly: true})
+
+	if _, err := s.cached("/wallet?", func() (any, error) { return nil, core.ErrorRiotRateLimit }); !errors.Is(err, core.ErrorRiotRateLimit) {
+		t.Fatalf("got %v", err)
+	}
+	if value, err := s.cached("/wallet?", func() (any, error) { return "wallet", nil }); err != nil || value != "wallet" {
+		t.Errorf("error was cached: %v, %v", value, err)
+	}
+}
+
+func TestCachedEvicts(t *testing.T) {
+	s := New(core.New(nil), Options{MetricsOnly: true, CacheTTL: time.Hour})
+
+	for i := 0; i < maxCacheEntries+50; i++ {
+		key := fmt.Sprintf("/mmr?player=%d", i)
+		s.cached(key, func() (any, error) { return key, nil })
+	}
+	if len(s.cache) != maxCacheEntries {
+		t.Errorf("cache holds %d responses, want at most %d", len(s.cache), maxCacheEntries)
+	}
+
+	s.opts.CacheTTL = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	s.cached("/loadout?", func() (any, error) { return "loadout", nil })
+	if len(s.cache) != 1 {
+		t.Errorf("cache holds %d responses after they all expired, want only the new one", len(s.cache))
+	}
+}
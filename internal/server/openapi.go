@@ -0,0 +1,60 @@
+package server
+
+import (
+	"net/http"
+)
+
+// openAPI describes the data routes as an OpenAPI 3 document, built from the route table
+// so it can't drift from what's actually served
+func (s *Server) openAPI(w http.ResponseWriter, req *http.Request) {
+	paths := map[string]any{}
+	for _, r := range s.routes {
+		parameters := []any{}
+		for _, p := range r.Params {
+			parameters = append(parameters, map[string]any{
+				"name":        p.Name,
+				"in":          "query",
+				"required":    false,
+				"description": p.Description,
+				"schema":      map[string]any{"type": "string"},
+			})
+		}
+
+		paths[r.Path] = map[string]any{
+			"get": map[string]any{
+				"summary":     r.Summary,
+				"description": r.Description,
+				"parameters":  parameters,
+				"responses": map[string]any{
+					"200": jsonResponse("The data, cached for "+s.opts.CacheTTL.String(), map[string]any{"type": "object"}),
+					"401": jsonResponse("Riot rejected the tokens and they couldn't be refreshed", errorSchema),
+					"429": jsonResponse("Riot is rate limiting requests", errorSchema),
+					"502": jsonResponse("Riot returned an error", errorSchema),
+				},
+			},
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "valocli",
+			"version":     "1",
+			"description": "VALORANT store, wallet, rank, match and loadout data of the logged in player",
+		},
+		"servers": []any{map[string]any{"url": "http://" + s.opts.Addr}},
+		"paths":   paths,
+	})
+}
+
+var errorSchema = map[string]any{
+	"type":       "object",
+	"properties": map[string]any{"error": map[string]any{"type": "string"}},
+}
+
+func jsonResponse(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content":     map[string]any{"application/json": map[string]any{"schema": schema}},
+	}
+}
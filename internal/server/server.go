@@ -0,0 +1,264 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/player"
+	"github.com/goamaan/valocli/internal/store"
+)
+
+const (
+	DefaultAddr     = "127.0.0.1:7878"
+	DefaultCacheTTL = time.Minute
+
+	refreshInterval = time.Minute
+
+	maxMatches = 20
+)
+
+type Options struct {
+	Addr     string
+	CacheTTL time.Duration
+	// Refresh gets the client new tokens, it's called shortly before they expire and
+	// whenever riot rejects them
+	Refresh func() error
+	// MetricsOnly leaves out the data routes, handlers added with Handle are still served
+	MetricsOnly bool
+}
+
+// Server keeps an authenticated client and serves valocli's data as json
+type Server struct {
+	session *core.Session
+	opts    Options
+	mux     *http.ServeMux
+	routes  []route
+
+	cacheMu  sync.Mutex
+	cache    map[string]cacheEntry
+	inflight map[string]*fetchCall
+}
+
+type route struct {
+	Path        string
+	Summary     string
+	Description string
+	Params      []param
+	fetch       func(r *http.Request) (any, error)
+}
+
+type param struct {
+	Name        string
+	Description string
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type walletBalance struct {
+	ID     string
+	Name   string
+	Amount int
+}
+
+type rankResponse struct {
+	player.PlayerRank
+	Error string `json:",omitempty"`
+}
+
+func New(c *core.Client, opts Options) *Server {
+	if opts.Addr == "" {
+		opts.Addr = DefaultAddr
+	}
+	if opts.CacheTTL == 0 {
+		opts.CacheTTL = DefaultCacheTTL
+	}
+
+	s := &Server{session: core.NewSession(c, opts.Refresh), opts: opts, mux: http.NewServeMux(), cache: map[string]cacheEntry{}, inflight: map[string]*fetchCall{}}
+	if opts.MetricsOnly {
+		return s
+	}
+
+	s.routes = []route{
+		{Path: "/store", Summary: "Daily store, featured bundles, night market and accessories", fetch: s.store},
+		{Path: "/wallet", Summary: "Balance of every currency", fetch: s.wallet},
+		{
+			Path: "/mmr", Summary: "Current and peak rank", fetch: s.mmr,
+			Description: "Your rank, or the ranks of other players when player is set",
+			Params:      []param{{"player", "comma separated riot ids (name#tag, with # escaped as %23) or puuids"}},
+		},
+		{
+			Path: "/matches", Summary: "Recent matches", fetch: s.matches,
+			Params: []param{{"queue", "only matches of this queue, e.g. competitive"}, {"count", fmt.Sprintf("number of matches, at most %d (default 10)", maxMatches)}},
+		},
+		{
+			Path: "/loadout", Summary: "Equipped skins, buddies, sprays, card and title", fetch: s.loadout,
+			Params: []param{{"puuid", "player to show the loadout of (default you)"}},
+		},
+	}
+
+	for _, r := range s.routes {
+		s.mux.HandleFunc(r.Path, s.handle(r))
+	}
+	s.mux.HandleFunc("/openapi.json", s.openAPI)
+
+	return s
+}
+
+// Handle adds another handler to the server, next to the data routes
+func (s *Server) Handle(path string, handler http.Handler) {
+	s.mux.Handle(path, handler)
+}
+
+func (s *Server) ListenAndServe() error {
+	go s.refreshLoop()
+
+	if s.opts.MetricsOnly {
+		log.Printf("serving valocli metrics on http://%s/metrics", s.opts.Addr)
+	} else {
+		log.Printf("serving valocli on http://%s (api description at /openapi.json)", s.opts.Addr)
+	}
+	return http.ListenAndServe(s.opts.Addr, s.mux)
+}
+
+func (s *Server) refreshLoop() {
+	for range time.Tick(refreshInterval) {
+		if err := s.session.RefreshIfExpiring(); err != nil {
+			log.Printf("could not refresh tokens: %s", err)
+		}
+	}
+}
+
+// Do runs fn with valid tokens, refreshing them and trying again once if riot rejects them
+func (s *Server) Do(fn func(c *core.Client) (any, error)) (any, error) {
+	return s.session.Do(fn)
+}
+
+func (s *Server) handle(r route) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"only GET is supported"})
+			return
+		}
+
+		key := req.URL.Path + "?" + req.URL.RawQuery
+		value, err := s.cached(key, func() (any, error) { return r.fetch(req) })
+		if err != nil {
+			writeJSON(w, statusFor(err), errorResponse{err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, value)
+	}
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, core.ErrorRiotNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, core.ErrorRiotRateLimit):
+		return http.StatusTooManyRequests
+	case errors.Is(err, core.ErrorRiotAuthentication):
+		return http.StatusUnauthorized
+	case errors.Is(err, errBadRequest):
+		return http.StatusBadRequest
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+var errBadRequest = errors.New("bad request")
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func (s *Server) store(r *http.Request) (any, error) {
+	return s.Do(func(c *core.Client) (any, error) {
+		return store.GetStoreTable(c)
+	})
+}
+
+func (s *Server) wallet(r *http.Request) (any, error) {
+	return s.Do(func(c *core.Client) (any, error) {
+		wallet, err := store.FetchWallet(c)
+		if err != nil {
+			return nil, err
+		}
+
+		currencies, err := store.GetCurrencies()
+		if err != nil {
+			return nil, err
+		}
+
+		balances := []walletBalance{}
+		for _, id := range currencies.SortedIds(wallet.Balances) {
+			balances = append(balances, walletBalance{ID: id, Name: currencies.Name(id), Amount: wallet.Balances[id]})
+		}
+
+		return balances, nil
+	})
+}
+
+func (s *Server) mmr(r *http.Request) (any, error) {
+	riotIds := r.URL.Query().Get("player")
+
+	return s.Do(func(c *core.Client) (any, error) {
+		if riotIds == "" {
+			return player.RankFor(c, c.AuthData.UserId)
+		}
+
+		ranks, err := player.GetPlayerRanks(c, strings.Split(riotIds, ","))
+		if err != nil {
+			return nil, err
+		}
+
+		responses := []rankResponse{}
+		for _, rank := range ranks {
+			response := rankResponse{PlayerRank: rank}
+			if rank.Err != nil {
+				response.Error = rank.Err.Error()
+			}
+			responses = append(responses, response)
+		}
+
+		return responses, nil
+	})
+}
+
+func (s *Server) matches(r *http.Request) (any, error) {
+	count := 10
+	if raw := r.URL.Query().Get("count"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxMatches {
+			return nil, fmt.Errorf("%w: count must be between 1 and %d", errBadRequest, maxMatches)
+		}
+		count = n
+	}
+
+	return s.Do(func(c *core.Client) (any, error) {
+		return player.RecentMatches(c, c.AuthData.UserId, r.URL.Query().Get("queue"), count)
+	})
+}
+
+func (s *Server) loadout(r *http.Request) (any, error) {
+	return s.Do(func(c *core.Client) (any, error) {
+		puuid := r.URL.Query().Get("puuid")
+		if puuid == "" {
+			puuid = c.AuthData.UserId
+		}
+
+		return player.Loadout(c, puuid)
+	})
+}
@@ -0,0 +1,193 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/i18n"
+)
+
+const DefaultPackCurrency = "USD"
+
+type VPPack struct {
+	Points int     `json:"points"`
+	Price  float64 `json:"price"`
+}
+
+// DefaultVPPacks are the standard VP pack prices, overridable per currency in the config file
+var DefaultVPPacks = map[string][]VPPack{
+	"USD": {{475, 4.99}, {1000, 9.99}, {2050, 19.99}, {3650, 34.99}, {5350, 49.99}, {11000, 99.99}},
+	"EUR": {{475, 4.99}, {1000, 9.99}, {2050, 19.99}, {3650, 34.99}, {5350, 49.99}, {11000, 99.99}},
+}
+
+type AffordableOffer struct {
+	Section   string
+	Item      string
+	Cost      Cost
+	Shortfall Cost
+}
+
+type PackSuggestion struct {
+	Packs []VPPack
+	Total float64
+}
+
+func (a AffordableOffer) Affordable() bool {
+	return len(a.Shortfall) == 0
+}
+
+func AnalyseAffordability(wallet *WalletResponse, table *StoreCliTable) []AffordableOffer {
+	offers := []AffordableOffer{}
+
+	add := func(section, item string, cost Cost) {
+		offers = append(offers, AffordableOffer{Section: section, Item: item, Cost: cost, Shortfall: cost.Shortfall(wallet.Balances)})
+	}
+
+	for _, item := range table.DailyStore {
+		add(i18n.T("Daily store"), item.Item, item.Cost)
+	}
+	for _, bundle := range table.Featured {
+		add(i18n.T("Featured store"), bundle.DisplayName, bundle.BundlePrice)
+	}
+	for _, item := range table.NightMarket {
+		add(i18n.T("Night Market"), item.Item, item.DiscountCost)
+	}
+	for _, item := range table.Accessories {
+		add(i18n.T("Accessories store"), item.Item, item.Cost)
+	}
+
+	return offers
+}
+
+// CheapestPackCombination finds the cheapest set of packs that adds up to at least the needed VP
+func CheapestPackCombination(packs []VPPack, needed int) (*PackSuggestion, error) {
+	if needed <= 0 {
+		return &PackSuggestion{}, nil
+	}
+	if len(packs) == 0 {
+		return nil, fmt.Errorf("no VP packs configured")
+	}
+
+	// cost[p] is the cheapest price for at least p points, choice[p] the pack bought last to get there
+	cost := make([]float64, needed+1)
+	choice := make([]int, needed+1)
+	for p := 1; p <= needed; p++ {
+		cost[p] = -1
+		for i, pack := range packs {
+			if pack.Points <= 0 {
+				continue
+			}
+			rest := p - pack.Points
+			if rest < 0 {
+				rest = 0
+			}
+			if cost[rest] < 0 {
+				continue
+			}
+			if candidate := cost[rest] + pack.Price; cost[p] < 0 || candidate < cost[p] {
+				cost[p] = candidate
+				choice[p] = i
+			}
+		}
+	}
+
+	if cost[needed] < 0 {
+		return nil, fmt.Errorf("no VP pack combination covers %d VP", needed)
+	}
+
+	suggestion := &PackSuggestion{Total: cost[needed]}
+	for p := needed; p > 0; {
+		pack := packs[choice[p]]
+		suggestion.Packs = append(suggestion.Packs, pack)
+		p -= pack.Points
+	}
+
+	sort.Slice(suggestion.Packs, func(i, j int) bool {
+		return suggestion.Packs[i].Points > suggestion.Packs[j].Points
+	})
+
+	return suggestion, nil
+}
+
+func GetAffordability(c *core.Client, buy string, packs []VPPack, currency string) error {
+	wallet, err := FetchWallet(c)
+	if err != nil {
+		return err
+	}
+
+	table, err := GetStoreTable(c)
+	if err != nil {
+		return err
+	}
+
+	offers := AnalyseAffordability(wallet, table)
+	PrintAffordability(wallet, offers, table.Currencies)
+
+	if buy == "" {
+		return nil
+	}
+
+	for _, offer := range offers {
+		if !strings.Contains(strings.ToLower(offer.Item), strings.ToLower(buy)) {
+			continue
+		}
+
+		fmt.Println()
+		if offer.Affordable() {
+			fmt.Printf("You can already afford %s (%s)\n", offer.Item, table.Currencies.FormatCost(offer.Cost))
+			return nil
+		}
+
+		fmt.Printf("To buy %s you need %s more\n", offer.Item, table.Currencies.FormatCost(offer.Shortfall))
+		if offer.Shortfall[ValorantPointsId] == 0 {
+			// only VP can be bought with real money
+			return nil
+		}
+
+		suggestion, err := CheapestPackCombination(packs, offer.Shortfall[ValorantPointsId])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Cheapest option: %s for %.2f %s\n", FormatPacks(suggestion.Packs), suggestion.Total, currency)
+		return nil
+	}
+
+	return fmt.Errorf("no offer in the current store matches %q", buy)
+}
+
+func FormatPacks(packs []VPPack) string {
+	counts := map[int]int{}
+	order := []int{}
+	for _, pack := range packs {
+		if counts[pack.Points] == 0 {
+			order = append(order, pack.Points)
+		}
+		counts[pack.Points]++
+	}
+
+	parts := []string{}
+	for _, points := range order {
+		parts = append(parts, fmt.Sprintf("%dx %d VP", counts[points], points))
+	}
+
+	return strings.Join(parts, " + ")
+}
+
+func PrintAffordability(wallet *WalletResponse, offers []AffordableOffer, currencies Currencies) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintln(w, i18n.Tf("💵 You have %s 💵", currencies.FormatCost(wallet.Balances)))
+	fmt.Fprintln(w, tableHeader("Store", "Item", "Price", "Status"))
+	for _, offer := range offers {
+		status := i18n.T("✅ affordable")
+		if !offer.Affordable() {
+			status = i18n.Tf("❌ %s short", currencies.FormatCost(offer.Shortfall))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", offer.Section, offer.Item, currencies.FormatCost(offer.Cost), status)
+	}
+	w.Flush()
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goamaan/valocli/internal/content"
+	"github.com/goamaan/valocli/internal/i18n"
+)
+
+const (
+	CurrenciesUrl = content.BaseUrl + "/currencies"
+)
+
+type Currency struct {
+	ID                  string `json:"uuid"`
+	DisplayName         string `json:"displayName"`
+	DisplayNameSingular string `json:"displayNameSingular"`
+	DisplayIcon         string `json:"displayIcon"`
+	LargeIcon           string `json:"largeIcon"`
+}
+
+// Currencies maps currency ids (as used in wallet balances and offer costs) to their content
+type Currencies map[string]Currency
+
+// Cost maps currency ids to amounts, the same shape riot uses for prices and balances
+type Cost map[string]int
+
+func GetCurrencies() (Currencies, error) {
+	var list []Currency
+	if err := content.Get(CurrenciesUrl, &list); err != nil {
+		return nil, err
+	}
+
+	currencies := Currencies{}
+	for _, currency := range list {
+		currencies[currency.ID] = currency
+	}
+
+	return currencies, nil
+}
+
+func (cs Currencies) Known(id string) bool {
+	_, ok := cs[id]
+	return ok
+}
+
+func (cs Currencies) Name(id string) string {
+	if currency, ok := cs[id]; ok {
+		return currency.DisplayName
+	}
+
+	return fmt.Sprintf("Unknown currency (%s)", id)
+}
+
+// SortedIds returns the ids in amounts, known currencies by name first, then unknown ones
+func (cs Currencies) SortedIds(amounts Cost) []string {
+	ids := make([]string, 0, len(amounts))
+	for id := range amounts {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		iKnown, jKnown := cs.Known(ids[i]), cs.Known(ids[j])
+		if iKnown != jKnown {
+			return iKnown
+		}
+		if iKnown {
+			return cs[ids[i]].DisplayName < cs[ids[j]].DisplayName
+		}
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+func (cs Currencies) FormatCost(cost Cost) string {
+	if len(cost) == 0 {
+		return i18n.T("Free")
+	}
+
+	parts := []string{}
+	for _, id := range cs.SortedIds(cost) {
+		parts = append(parts, fmt.Sprintf("%d %s", cost[id], cs.Name(id)))
+	}
+
+	return strings.Join(parts, " + ")
+}
+
+// Shortfall returns how much of each currency in cost is missing from balances
+func (cost Cost) Shortfall(balances Cost) Cost {
+	shortfall := Cost{}
+	for id, amount := range cost {
+		if missing := amount - balances[id]; missing > 0 {
+			shortfall[id] = missing
+		}
+	}
+
+	return shortfall
+}
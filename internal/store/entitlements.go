@@ -0,0 +1,50 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/goamaan/valocli/internal/core"
+)
+
+const (
+	EntitlementsUrl = "https://pd.%s.a.pvp.net/store/v1/entitlements/%s/%s"
+)
+
+type EntitlementsResponse struct {
+	ItemTypeID   string `json:"ItemTypeID"`
+	Entitlements []struct {
+		TypeID     string `json:"TypeID"`
+		ItemID     string `json:"ItemID"`
+		InstanceID string `json:"InstanceID,omitempty"`
+	} `json:"Entitlements"`
+}
+
+func FetchEntitlements(c *core.Client, itemTypeId string) (*EntitlementsResponse, error) {
+	url := fmt.Sprintf(EntitlementsUrl, c.Shard(), c.AuthData.UserId, itemTypeId)
+	req, err := c.RequestWithClientInfo("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(EntitlementsResponse)
+	if err = c.DoJSON(req, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// OwnedItems returns the ids of every item of the given type the player owns
+func OwnedItems(c *core.Client, itemTypeId string) (map[string]bool, error) {
+	entitlements, err := FetchEntitlements(c, itemTypeId)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := map[string]bool{}
+	for _, entitlement := range entitlements.Entitlements {
+		owned[entitlement.ItemID] = true
+	}
+
+	return owned, nil
+}
@@ -1,20 +1,23 @@
 package store
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"log"
-	"net/http"
+	"math"
 	"os"
-	"strconv"
+	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/goamaan/valocli/internal/content"
 	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/i18n"
 )
 
 const (
 	StorefrontUrl  = "https://pd.%s.a.pvp.net/store/v2/storefront/%s"
-	BundleIdUrl    = "https://valorant-api.com/v1/bundles/%s"
+	BundleIdUrl    = content.BaseUrl + "/bundles/%s"
 	AgentsId       = "01bb38e1-da47-4e6a-9b3d-945fe4655707"
 	ContractsId    = "f85cb6f7-33e5-4dc8-b609-ec7212301948"
 	SpraysId       = "d5f120f8-ff8c-4aac-92ea-f2b5acbe9475"
@@ -26,34 +29,47 @@ const (
 )
 
 var SingleItemUrlMap = map[string]string{
-	SkinsId:        "https://valorant-api.com/v1/weapons/skinlevels/%s",
-	SkinVariantsId: "https://valorant-api.com/v1/weapons/skinchromas/%s",
-	AgentsId:       "https://valorant-api.com/v1/agents/%s",
-	ContractsId:    "https://valorant-api.com/v1/contracts/%s",
-	SpraysId:       "https://valorant-api.com/v1/sprays/%s",
-	GunBuddiesId:   "https://valorant-api.com/v1/buddies/levels/%s",
-	CardsId:        "https://valorant-api.com/v1/playercards/%s",
-	TitlesId:       "https://valorant-api.com/v1/playertitles/%s",
+	SkinsId:        content.BaseUrl + "/weapons/skinlevels/%s",
+	SkinVariantsId: content.BaseUrl + "/weapons/skinchromas/%s",
+	AgentsId:       content.BaseUrl + "/agents/%s",
+	ContractsId:    content.BaseUrl + "/contracts/%s",
+	SpraysId:       content.BaseUrl + "/sprays/%s",
+	GunBuddiesId:   content.BaseUrl + "/buddies/levels/%s",
+	CardsId:        content.BaseUrl + "/playercards/%s",
+	TitlesId:       content.BaseUrl + "/playertitles/%s",
 }
 
 type Item struct {
 	Item        string
-	Cost        int
+	Cost        Cost
 	DisplayIcon string
 }
 
 type NightMarketItem struct {
 	Item            string
-	BaseCost        int
-	DiscountCost    int
+	BaseCost        Cost
+	DiscountCost    Cost
 	DiscountPercent int
 	DisplayIcon     string
 }
 
+type BundleItem struct {
+	Item            string
+	DisplayIcon     string
+	Quantity        int
+	BasePrice       Cost
+	DiscountedPrice Cost
+	DiscountPercent int
+	IsPromoItem     bool
+}
+
 type Bundle struct {
-	Items       []Item
-	BundlePrice int
+	Items       []BundleItem
+	BundlePrice Cost
+	BasePrice   Cost
+	Savings     Cost
 	DisplayName string
+	Remaining   time.Duration
 }
 
 type StoreCliTable struct {
@@ -61,62 +77,59 @@ type StoreCliTable struct {
 	DailyStore  []Item
 	Accessories []Item
 	NightMarket []NightMarketItem
+	Currencies  Currencies
+
+	FetchedAt            time.Time
+	FeaturedRemaining    time.Duration
+	DailyStoreRemaining  time.Duration
+	AccessoriesRemaining time.Duration
+	NightMarketRemaining time.Duration
+}
+
+type ExternalApiItem struct {
+	DisplayName string `json:"displayName"`
+	DisplayIcon string `json:"displayIcon"`
 }
 
-type ExternalApiSkinResponse struct {
-	Data struct {
-		DisplayName string `json:"displayName"`
-		DisplayIcon string `json:"displayIcon"`
-	} `json:"data"`
+type StorefrontBundle struct {
+	ID                         string                 `json:"ID"`
+	DataAssetID                string                 `json:"DataAssetID"`
+	CurrencyID                 string                 `json:"CurrencyID"`
+	Items                      []StorefrontBundleItem `json:"Items"`
+	TotalBaseCost              Cost                   `json:"TotalBaseCost"`
+	TotalDiscountedCost        Cost                   `json:"TotalDiscountedCost"`
+	TotalDiscountPercent       float64                `json:"TotalDiscountPercent"`
+	DurationRemainingInSeconds int                    `json:"DurationRemainingInSeconds"`
+	WholesaleOnly              bool                   `json:"WholesaleOnly"`
+}
+
+type StorefrontBundleItem struct {
+	Item struct {
+		ItemTypeID string `json:"ItemTypeID"`
+		ItemID     string `json:"ItemID"`
+		Quantity   int    `json:"Quantity"`
+	} `json:"Item"`
+	BasePrice       int     `json:"BasePrice"`
+	CurrencyID      string  `json:"CurrencyID"`
+	DiscountPercent float64 `json:"DiscountPercent"`
+	DiscountedPrice float64 `json:"DiscountedPrice"`
+	IsPromoItem     bool    `json:"IsPromoItem"`
 }
 
 type StorefrontResponse struct {
 	FeaturedBundle struct {
-		Bundle struct {
-			ID          string `json:"ID"`
-			DataAssetID string `json:"DataAssetID"`
-			CurrencyID  string `json:"CurrencyID"`
-			Items       []struct {
-				Item struct {
-					ItemTypeID string `json:"ItemTypeID"`
-					ItemID     string `json:"ItemID"`
-					Quantity   int    `json:"Quantity"`
-				} `json:"Item"`
-				BasePrice       int     `json:"BasePrice"`
-				CurrencyID      string  `json:"CurrencyID"`
-				DiscountPercent float64 `json:"DiscountPercent"`
-				DiscountedPrice float64 `json:"DiscountedPrice"`
-				IsPromoItem     bool    `json:"IsPromoItem"`
-			} `json:"Items"`
-		} `json:"Bundle"`
-		Bundles []struct {
-			ID          string `json:"ID"`
-			DataAssetID string `json:"DataAssetID"`
-			CurrencyID  string `json:"CurrencyID"`
-			Items       []struct {
-				Item struct {
-					ItemTypeID string `json:"ItemTypeID"`
-					ItemID     string `json:"ItemID"`
-					Quantity   int    `json:"Quantity"`
-				} `json:"Item"`
-				BasePrice       int     `json:"BasePrice"`
-				CurrencyID      string  `json:"CurrencyID"`
-				DiscountPercent float64 `json:"DiscountPercent"`
-				DiscountedPrice float64 `json:"DiscountedPrice"`
-				IsPromoItem     bool    `json:"IsPromoItem"`
-			} `json:"Items"`
-			TotalDiscountedCost        map[string]int `json:"TotalDiscountedCost"`
-			DurationRemainingInSeconds int            `json:"DurationRemainingInSeconds"`
-		} `json:"Bundles"`
-		BundleRemainingDurationInSeconds int `json:"BundleRemainingDurationInSeconds"`
+		// Bundle is the legacy single featured bundle, still sent alongside Bundles
+		Bundle                           StorefrontBundle   `json:"Bundle"`
+		Bundles                          []StorefrontBundle `json:"Bundles"`
+		BundleRemainingDurationInSeconds int                `json:"BundleRemainingDurationInSeconds"`
 	} `json:"FeaturedBundle"`
 	SkinsPanelLayout struct {
 		SingleItemOffers      []string `json:"SingleItemOffers"`
 		SingleItemStoreOffers []struct {
-			OfferID          string         `json:"OfferID"`
-			IsDirectPurchase bool           `json:"IsDirectPurchase"`
-			StartDate        string         `json:"StartDate"`
-			Cost             map[string]int `json:"Cost"`
+			OfferID          string `json:"OfferID"`
+			IsDirectPurchase bool   `json:"IsDirectPurchase"`
+			StartDate        string `json:"StartDate"`
+			Cost             Cost   `json:"Cost"`
 			Rewards          []struct {
 				ItemTypeID string `json:"ItemTypeID"`
 				ItemID     string `json:"ItemID"`
@@ -130,10 +143,10 @@ type StorefrontResponse struct {
 			OfferID          string `json:"OfferID"`
 			StorefrontItemID string `json:"StorefrontItemID"`
 			Offer            struct {
-				OfferID          string         `json:"OfferID"`
-				IsDirectPurchase bool           `json:"IsDirectPurchase"`
-				StartDate        string         `json:"StartDate"`
-				Cost             map[string]int `json:"Cost"`
+				OfferID          string `json:"OfferID"`
+				IsDirectPurchase bool   `json:"IsDirectPurchase"`
+				StartDate        string `json:"StartDate"`
+				Cost             Cost   `json:"Cost"`
 				Rewards          []struct {
 					ItemTypeID string `json:"ItemTypeID"`
 					ItemID     string `json:"ItemID"`
@@ -146,29 +159,29 @@ type StorefrontResponse struct {
 		BonusStoreOffers []struct {
 			BonusOfferID string `json:"BonusOfferID"`
 			Offer        struct {
-				OfferID          string         `json:"OfferID"`
-				IsDirectPurchase bool           `json:"IsDirectPurchase"`
-				StartDate        string         `json:"StartDate"`
-				Cost             map[string]int `json:"Cost"`
+				OfferID          string `json:"OfferID"`
+				IsDirectPurchase bool   `json:"IsDirectPurchase"`
+				StartDate        string `json:"StartDate"`
+				Cost             Cost   `json:"Cost"`
 				Rewards          []struct {
 					ItemTypeID string `json:"ItemTypeID"`
 					ItemID     string `json:"ItemID"`
 					Quantity   int    `json:"Quantity"`
 				} `json:"Rewards"`
 			} `json:"Offer"`
-			DiscountPercent float64        `json:"DiscountPercent"`
-			DiscountCosts   map[string]int `json:"DiscountCosts"`
-			IsSeen          bool           `json:"IsSeen"`
+			DiscountPercent float64 `json:"DiscountPercent"`
+			DiscountCosts   Cost    `json:"DiscountCosts"`
+			IsSeen          bool    `json:"IsSeen"`
 		} `json:"BonusStoreOffers"`
 		BonusStoreRemainingDurationInSeconds int `json:"BonusStoreRemainingDurationInSeconds"`
 	} `json:"BonusStore,omitempty"`
 	AccessoryStore struct {
 		AccessoryStoreOffers []struct {
 			Offer struct {
-				OfferID          string         `json:"OfferID"`
-				IsDirectPurchase bool           `json:"IsDirectPurchase"`
-				StartDate        string         `json:"StartDate"`
-				Cost             map[string]int `json:"Cost"`
+				OfferID          string `json:"OfferID"`
+				IsDirectPurchase bool   `json:"IsDirectPurchase"`
+				StartDate        string `json:"StartDate"`
+				Cost             Cost   `json:"Cost"`
 				Rewards          []struct {
 					ItemTypeID string `json:"ItemTypeID"`
 					ItemID     string `json:"ItemID"`
@@ -176,113 +189,142 @@ type StorefrontResponse struct {
 				} `json:"Rewards"`
 			} `json:"Offer"`
 		} `json:"AccessoryStoreOffers"`
+		AccessoryStoreRemainingDurationInSeconds int `json:"AccessoryStoreRemainingDurationInSeconds"`
 	} `json:"AccessoryStore"`
 }
 
-func GetStorefront(c *core.Client) error {
-	url := fmt.Sprintf(StorefrontUrl, c.Region, c.AuthData.UserId)
+func FetchStorefront(c *core.Client) (*StorefrontResponse, error) {
+	url := fmt.Sprintf(StorefrontUrl, c.Shard(), c.AuthData.UserId)
 	req, err := c.RequestWithAuth("GET", url, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	res, err := c.HttpClient.Do(req)
-	if err != nil {
-		return err
+	storefrontBody := new(StorefrontResponse)
+	if err = c.DoJSON(req, storefrontBody); err != nil {
+		return nil, err
 	}
 
-	defer res.Body.Close()
-	storefrontBody := new(StorefrontResponse)
-	if err = json.NewDecoder(res.Body).Decode(&storefrontBody); err != nil {
-		return err
+	return storefrontBody, nil
+}
+
+func GetStoreTable(c *core.Client) (*StoreCliTable, error) {
+	storefrontBody, err := FetchStorefront(c)
+	if err != nil {
+		return nil, err
 	}
 
 	storeCliTable := &StoreCliTable{}
+	if err = FetchStores(storefrontBody, storeCliTable); err != nil {
+		return nil, err
+	}
 
-	err = FetchStores(storefrontBody, storeCliTable)
+	return storeCliTable, nil
+}
 
+func GetStorefront(c *core.Client) error {
+	storeCliTable, err := GetStoreTable(c)
 	if err != nil {
-		log.Fatalf("Error in fetching store items from external API:  %s", err)
+		return err
 	}
 
+	PrintStore(storeCliTable)
+
 	return nil
 }
 
+// NextDailyReset returns the local time at which the daily store rotates next
+func NextDailyReset(c *core.Client) (time.Time, error) {
+	storefrontBody, err := FetchStorefront(c)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Now().Add(secondsToDuration(storefrontBody.SkinsPanelLayout.SingleItemOffersRemainingDurationInSeconds)), nil
+}
+
 func FetchStores(s *StorefrontResponse, table *StoreCliTable) error {
+	currencies, err := GetCurrencies()
+	if err != nil {
+		return err
+	}
+
+	table.Currencies = currencies
+	table.FetchedAt = time.Now()
+	table.DailyStoreRemaining = secondsToDuration(s.SkinsPanelLayout.SingleItemOffersRemainingDurationInSeconds)
+	table.FeaturedRemaining = secondsToDuration(s.FeaturedBundle.BundleRemainingDurationInSeconds)
+	table.AccessoriesRemaining = secondsToDuration(s.AccessoryStore.AccessoryStoreRemainingDurationInSeconds)
+	if s.BonusStore != nil {
+		table.NightMarketRemaining = secondsToDuration(s.BonusStore.BonusStoreRemainingDurationInSeconds)
+	}
+
 	// Daily store
 	log.Println("Fetching Daily Store...")
 	for _, offer := range s.SkinsPanelLayout.SingleItemStoreOffers {
-		itemId := offer.Rewards[0].ItemID
-		requestUrl := SingleItemUrlMap[offer.Rewards[0].ItemTypeID]
-		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(requestUrl, itemId), nil)
-		if err != nil {
-			log.Fatalf("Error creating http request to external api: %s", err)
-			return err
-		}
-
-		res, err := http.DefaultClient.Do(req)
+		item, err := FetchItem(offer.Rewards[0].ItemTypeID, offer.Rewards[0].ItemID)
 		if err != nil {
 			return err
 		}
 
-		responseBody := new(ExternalApiSkinResponse)
-		if err = json.NewDecoder(res.Body).Decode(&responseBody); err != nil {
-			log.Fatalf("Error decoding json from external api response: %s", err)
-			return err
-		}
-
-		table.DailyStore = append(table.DailyStore, Item{Cost: offer.Cost[ValorantPointsId], Item: responseBody.Data.DisplayName, DisplayIcon: responseBody.Data.DisplayIcon})
-		res.Body.Close()
+		table.DailyStore = append(table.DailyStore, Item{Cost: offer.Cost, Item: item.DisplayName, DisplayIcon: item.DisplayIcon})
 	}
 
 	// Featured bundles
 	log.Println("Fetching Featured Store...")
-	for _, featuredBundle := range s.FeaturedBundle.Bundles {
+	for _, featuredBundle := range s.FeaturedBundles() {
 		bundle := new(Bundle)
-		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(BundleIdUrl, featuredBundle.DataAssetID), nil)
-		if err != nil {
-			log.Fatalf("Error creating http request for bundle: %s", err)
+		bundleContent := new(ExternalApiItem)
+		if err := content.Get(fmt.Sprintf(BundleIdUrl, featuredBundle.DataAssetID), bundleContent); err != nil {
 			return err
 		}
 
-		res, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return err
-		}
-
-		responseBody := new(ExternalApiSkinResponse)
-		if err = json.NewDecoder(res.Body).Decode(&responseBody); err != nil {
-			log.Fatalf("Error decoding json from external api response: %s", err)
-			return err
+		bundle.DisplayName = bundleContent.DisplayName
+		bundle.Remaining = secondsToDuration(featuredBundle.DurationRemainingInSeconds)
+		if featuredBundle.DurationRemainingInSeconds == 0 {
+			bundle.Remaining = table.FeaturedRemaining
 		}
 
-		bundle.BundlePrice = featuredBundle.TotalDiscountedCost[ValorantPointsId]
-		bundle.DisplayName = responseBody.Data.DisplayName
-		for _, item := range featuredBundle.Items {
-			requestUrl := SingleItemUrlMap[item.Item.ItemTypeID]
-			itemId := item.Item.ItemID
-			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(requestUrl, itemId), nil)
+		bundle.BasePrice = Cost{}
+		bundle.BundlePrice = Cost{}
+		for _, bundleItem := range featuredBundle.Items {
+			item, err := FetchItem(bundleItem.Item.ItemTypeID, bundleItem.Item.ItemID)
 			if err != nil {
-				log.Fatalf("Error creating http request to external api: %s", err)
 				return err
 			}
 
-			res, err := http.DefaultClient.Do(req)
-			if err != nil {
-				return err
+			discountedPrice := Cost{}
+			if price := int(bundleItem.DiscountedPrice); price > 0 {
+				discountedPrice[bundleItem.CurrencyID] = price
 			}
 
-			responseBody := new(ExternalApiSkinResponse)
-			if err = json.NewDecoder(res.Body).Decode(&responseBody); err != nil {
-				log.Fatalf("Error decoding json from external api response: %s", err)
-				return err
-			}
+			bundle.BasePrice[bundleItem.CurrencyID] += bundleItem.BasePrice
+			bundle.BundlePrice[bundleItem.CurrencyID] += discountedPrice[bundleItem.CurrencyID]
+			bundle.Items = append(bundle.Items, BundleItem{
+				Item:            item.DisplayName,
+				DisplayIcon:     item.DisplayIcon,
+				Quantity:        bundleItem.Item.Quantity,
+				BasePrice:       Cost{bundleItem.CurrencyID: bundleItem.BasePrice},
+				DiscountedPrice: discountedPrice,
+				DiscountPercent: fractionToPercent(bundleItem.DiscountPercent),
+				IsPromoItem:     bundleItem.IsPromoItem,
+			})
+		}
+
+		// prefer riot's totals, the legacy bundle doesn't always send them
+		if len(featuredBundle.TotalBaseCost) > 0 {
+			bundle.BasePrice = featuredBundle.TotalBaseCost
+		}
+		if len(featuredBundle.TotalDiscountedCost) > 0 {
+			bundle.BundlePrice = featuredBundle.TotalDiscountedCost
+		}
 
-			bundle.Items = append(bundle.Items, Item{Cost: item.BasePrice, Item: responseBody.Data.DisplayName, DisplayIcon: responseBody.Data.DisplayIcon})
-			res.Body.Close()
+		bundle.Savings = Cost{}
+		for currencyId, amount := range bundle.BasePrice {
+			if saved := amount - bundle.BundlePrice[currencyId]; saved > 0 {
+				bundle.Savings[currencyId] = saved
+			}
 		}
 
-		res.Body.Close()
 		table.Featured = append(table.Featured, *bundle)
 	}
 
@@ -290,106 +332,194 @@ func FetchStores(s *StorefrontResponse, table *StoreCliTable) error {
 	if s.BonusStore != nil {
 		log.Println("Fetching Night Market...")
 		for _, offer := range s.BonusStore.BonusStoreOffers {
-			itemId := offer.Offer.Rewards[0].ItemID
-			requestUrl := SingleItemUrlMap[offer.Offer.Rewards[0].ItemTypeID]
-			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(requestUrl, itemId), nil)
+			item, err := FetchItem(offer.Offer.Rewards[0].ItemTypeID, offer.Offer.Rewards[0].ItemID)
 			if err != nil {
-				log.Fatalf("Error creating http request to external api: %s", err)
-				return err
-			}
-
-			res, err := http.DefaultClient.Do(req)
-			if err != nil {
-				return err
-			}
-
-			responseBody := new(ExternalApiSkinResponse)
-			if err = json.NewDecoder(res.Body).Decode(&responseBody); err != nil {
-				log.Fatalf("Error decoding json from external api response: %s", err)
 				return err
 			}
 
 			table.NightMarket = append(table.NightMarket,
-				NightMarketItem{BaseCost: offer.Offer.Cost[ValorantPointsId],
-					Item:            responseBody.Data.DisplayName,
-					DiscountCost:    offer.DiscountCosts[ValorantPointsId],
-					DiscountPercent: int(offer.DiscountPercent),
-					DisplayIcon:     responseBody.Data.DisplayIcon})
-
-			res.Body.Close()
+				NightMarketItem{BaseCost: offer.Offer.Cost,
+					Item:            item.DisplayName,
+					DiscountCost:    offer.DiscountCosts,
+					DiscountPercent: int(math.Round(offer.DiscountPercent)),
+					DisplayIcon:     item.DisplayIcon})
 		}
 	}
 
 	// Accessory Store
 	log.Println("Fetching Accessories Store")
 	for _, offer := range s.AccessoryStore.AccessoryStoreOffers {
-		itemId := offer.Offer.Rewards[0].ItemID
-		requestUrl := SingleItemUrlMap[offer.Offer.Rewards[0].ItemTypeID]
-		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(requestUrl, itemId), nil)
+		item, err := FetchItem(offer.Offer.Rewards[0].ItemTypeID, offer.Offer.Rewards[0].ItemID)
 		if err != nil {
-			log.Fatalf("Error creating http request to external api: %s", err)
 			return err
 		}
 
-		res, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return err
-		}
+		table.Accessories = append(table.Accessories,
+			Item{
+				Item:        item.DisplayName,
+				Cost:        offer.Offer.Cost,
+				DisplayIcon: item.DisplayIcon})
+	}
 
-		responseBody := new(ExternalApiSkinResponse)
-		if err = json.NewDecoder(res.Body).Decode(&responseBody); err != nil {
-			log.Fatalf("Error decoding json from external api response: %s", err)
-			return err
+	return nil
+}
+
+// Live returns a copy of the table with every countdown moved on to now, for displays that
+// stay up longer than a refresh
+func (table *StoreCliTable) Live() *StoreCliTable {
+	elapsed := time.Since(table.FetchedAt)
+	live := *table
+	live.FetchedAt = table.FetchedAt.Add(elapsed)
+	live.DailyStoreRemaining -= elapsed
+	live.FeaturedRemaining -= elapsed
+	live.AccessoriesRemaining -= elapsed
+	live.NightMarketRemaining -= elapsed
+
+	live.Featured = make([]Bundle, len(table.Featured))
+	for i, bundle := range table.Featured {
+		bundle.Remaining -= elapsed
+		live.Featured[i] = bundle
+	}
+
+	return &live
+}
+
+// FeaturedBundles merges the legacy single featured bundle with the bundle list
+func (s *StorefrontResponse) FeaturedBundles() []StorefrontBundle {
+	bundles := s.FeaturedBundle.Bundles
+	legacy := s.FeaturedBundle.Bundle
+	if legacy.ID == "" {
+		return bundles
+	}
+
+	for _, bundle := range bundles {
+		if bundle.ID == legacy.ID {
+			return bundles
 		}
+	}
 
-		table.Accessories = append(table.Accessories,
-			Item{
-				Item:        responseBody.Data.DisplayName,
-				Cost:        offer.Offer.Cost[KingdomCreditsId],
-				DisplayIcon: responseBody.Data.DisplayIcon})
+	return append([]StorefrontBundle{legacy}, bundles...)
+}
 
-		res.Body.Close()
+// FetchItem looks up the display name and icon of an item from the content catalog
+func FetchItem(itemTypeId, itemId string) (*ExternalApiItem, error) {
+	requestUrl, ok := SingleItemUrlMap[itemTypeId]
+	if !ok {
+		return nil, fmt.Errorf("unknown item type %s for item %s", itemTypeId, itemId)
 	}
 
-	PrintStore(table)
+	item := new(ExternalApiItem)
+	if err := content.Get(fmt.Sprintf(requestUrl, itemId), item); err != nil {
+		return nil, err
+	}
 
-	return nil
+	return item, nil
 }
 
 func PrintStore(table *StoreCliTable) {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
-	fmt.Fprintln(w, "💰 Daily store 💰")
-	fmt.Fprintln(w, "Skin\tPrice\tImage Link")
+	FprintStore(os.Stdout, table)
+}
+
+func FprintStore(out io.Writer, table *StoreCliTable) {
+	cost := table.Currencies.FormatCost
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintf(w, "💰 %s 💰\n", i18n.T("Daily store"))
+	fmt.Fprintln(w, resetLine(table.FetchedAt, table.DailyStoreRemaining))
+	fmt.Fprintln(w, tableHeader("Skin", "Price", "Image Link"))
 	for _, item := range table.DailyStore {
-		fmt.Fprintln(w, fmt.Sprintf("%s\t%d\t%s", item.Item, item.Cost, item.DisplayIcon))
+		fmt.Fprintln(w, fmt.Sprintf("%s\t%s\t%s", item.Item, cost(item.Cost), item.DisplayIcon))
 	}
 	fmt.Fprintln(w, "★★★★★★★★★★★★★★★★")
-	fmt.Fprintln(w, "💰 Featured store 💰")
+	fmt.Fprintf(w, "💰 %s 💰\n", i18n.T("Featured store"))
+	fmt.Fprintln(w, resetLine(table.FetchedAt, table.FeaturedRemaining))
 	for _, bundle := range table.Featured {
 		fmt.Fprintln(w, bundle.DisplayName)
-		fmt.Fprintln(w, fmt.Sprintf("%sVP", strconv.Itoa(bundle.BundlePrice)))
-		fmt.Fprintln(w, "Skin\tPrice\tImage Link")
+		fmt.Fprintln(w, resetLine(table.FetchedAt, bundle.Remaining))
+		if len(bundle.Savings) > 0 {
+			fmt.Fprintln(w, i18n.Tf("Bundle price: %s (%s if bought separately, you save %s)",
+				cost(bundle.BundlePrice), cost(bundle.BasePrice), cost(bundle.Savings)))
+		} else {
+			fmt.Fprintln(w, i18n.Tf("Bundle price: %s", cost(bundle.BundlePrice)))
+		}
+		fmt.Fprintln(w, tableHeader("Skin", "Quantity", "Base Price", "Bundle Price", "Discount Percent", "Image Link"))
 		for _, item := range bundle.Items {
-			fmt.Fprintln(w, fmt.Sprintf("%s\t%d\t%s", item.Item, item.Cost, item.DisplayIcon))
+			name := item.Item
+			if item.IsPromoItem {
+				name = fmt.Sprintf("%s 🎁 %s", name, i18n.T("promo"))
+			}
+			fmt.Fprintln(w, fmt.Sprintf("%s\t%d\t%s\t%s\t%d\t%s",
+				name,
+				item.Quantity,
+				cost(item.BasePrice),
+				cost(item.DiscountedPrice),
+				item.DiscountPercent,
+				item.DisplayIcon))
 		}
 	}
 	fmt.Fprintln(w, "★★★★★★★★★★★★★★★★")
-	fmt.Fprintln(w, "🌟 Night Market 🌟")
-	fmt.Fprintln(w, "Skin\tBase Price\tDiscount Price\tDiscount Percent\tImage Link")
+	fmt.Fprintf(w, "🌟 %s 🌟\n", i18n.T("Night Market"))
+	if table.NightMarket == nil {
+		fmt.Fprintln(w, i18n.T("Night market is closed"))
+	} else {
+		fmt.Fprintln(w, resetLine(table.FetchedAt, table.NightMarketRemaining))
+	}
+	fmt.Fprintln(w, tableHeader("Skin", "Base Price", "Discount Price", "Discount Percent", "Image Link"))
 	for _, item := range table.NightMarket {
 		fmt.Fprintln(w,
-			fmt.Sprintf("%s\t%d\t%d\t%d\t%s",
+			fmt.Sprintf("%s\t%s\t%s\t%d\t%s",
 				item.Item,
-				item.BaseCost,
-				item.DiscountCost,
+				cost(item.BaseCost),
+				cost(item.DiscountCost),
 				item.DiscountPercent,
 				item.DisplayIcon))
 	}
 	fmt.Fprintln(w, "★★★★★★★★★★★★★★★★")
-	fmt.Fprintln(w, "🌟 Accessories store 🌟")
-	fmt.Fprintln(w, "Item\tPrice\tImage Link")
+	fmt.Fprintf(w, "🌟 %s 🌟\n", i18n.T("Accessories store"))
+	fmt.Fprintln(w, resetLine(table.FetchedAt, table.AccessoriesRemaining))
+	fmt.Fprintln(w, tableHeader("Item", "Price", "Image Link"))
 	for _, item := range table.Accessories {
-		fmt.Fprintln(w, fmt.Sprintf("%s\t%d\t%s", item.Item, item.Cost, item.DisplayIcon))
+		fmt.Fprintln(w, fmt.Sprintf("%s\t%s\t%s", item.Item, cost(item.Cost), item.DisplayIcon))
 	}
 	w.Flush()
 }
+
+func tableHeader(columns ...string) string {
+	translated := make([]string, len(columns))
+	for i, column := range columns {
+		translated[i] = i18n.T(column)
+	}
+
+	return strings.Join(translated, "\t")
+}
+
+func resetLine(fetchedAt time.Time, remaining time.Duration) string {
+	resetsAt := fetchedAt.Add(remaining).Local()
+	return i18n.Tf("⏳ Resets in %s (%s)", FormatCountdown(remaining), resetsAt.Format("Mon 02 Jan 15:04"))
+}
+
+// FormatCountdown renders a duration as e.g. "2d 3h 04m" or "13h 04m 12s"
+func FormatCountdown(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	seconds := int(d.Seconds()) % 60
+
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %02dm", days, hours, minutes)
+	}
+
+	return fmt.Sprintf("%dh %02dm %02ds", hours, minutes, seconds)
+}
+
+// bundle items send their discount as a fraction, unlike the night market which sends a percentage
+func fractionToPercent(fraction float64) int {
+	return int(math.Round(fraction * 100))
+}
+
+func secondsToDuration(seconds int) time.Duration {
+	return time.Duration(seconds) * time.Second
+}
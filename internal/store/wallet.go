@@ -1,49 +1,76 @@
 package store
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/i18n"
 )
 
 const (
 	WalletUrl         = "https://pd.%s.a.pvp.net/store/v1/wallet/%s"
 	ValorantPointsId  = "85ad13f7-3d1b-5128-9eb2-7cd8ee0b5741"
 	KingdomCreditsId  = "85ca954a-41f2-ce94-9b45-8ca3dd39a00d"
-	RadianitePointsId = "59aa87c-4cbf-517a-5983-6e81511be9b7"
+	RadianitePointsId = "e59aa87c-4cbf-517a-5983-6e81511be9b7"
 	FreeAgentsId      = "f08d4ae3-939c-4576-ab26-09ce1f23bb37"
 )
 
 type WalletResponse struct {
-	Balances map[string]int `json:"Balances"`
+	Balances Cost `json:"Balances"`
 }
 
-func GetWallet(c *core.Client) error {
-	url := fmt.Sprintf(WalletUrl, c.Region, c.AuthData.UserId)
+func FetchWallet(c *core.Client) (*WalletResponse, error) {
+	url := fmt.Sprintf(WalletUrl, c.Shard(), c.AuthData.UserId)
 	req, err := c.RequestWithAuth("GET", url, nil)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	walletBody := new(WalletResponse)
+	if err = c.DoJSON(req, walletBody); err != nil {
+		return nil, err
 	}
 
-	res, err := c.HttpClient.Do(req)
+	return walletBody, nil
+}
+
+func GetWallet(c *core.Client) error {
+	walletBody, err := FetchWallet(c)
 	if err != nil {
 		return err
 	}
 
-	defer res.Body.Close()
-	walletBody := new(WalletResponse)
-	if err = json.NewDecoder(res.Body).Decode(&walletBody); err != nil {
+	currencies, err := GetCurrencies()
+	if err != nil {
 		return err
 	}
-	PrintWallet(walletBody)
+
+	PrintWallet(walletBody, currencies)
 	return nil
 }
 
-func PrintWallet(wallet *WalletResponse) {
-	fmt.Println("💵 Balances 💵")
-	fmt.Printf("Valorant Points (VP) - %d\n", wallet.Balances[ValorantPointsId])
-	fmt.Printf("Radianite Points (RP) - %d\n", wallet.Balances[RadianitePointsId])
-	fmt.Printf("Kingdomt Credits - %d\n", wallet.Balances[KingdomCreditsId])
-	fmt.Printf("Free Agents - %d\n", wallet.Balances[FreeAgentsId])
+func PrintWallet(wallet *WalletResponse, currencies Currencies) {
+	FprintWallet(os.Stdout, wallet, currencies)
+}
+
+func FprintWallet(out io.Writer, wallet *WalletResponse, currencies Currencies) {
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', tabwriter.Debug|tabwriter.TabIndent)
+	fmt.Fprintf(w, "💵 %s 💵\n", i18n.T("Balances"))
+	fmt.Fprintln(w, tableHeader("Currency", "Balance", "Image Link"))
+	unknown := []string{}
+	for _, id := range currencies.SortedIds(wallet.Balances) {
+		if !currencies.Known(id) {
+			unknown = append(unknown, id)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", currencies.Name(id), wallet.Balances[id], currencies[id].DisplayIcon)
+	}
+	w.Flush()
+
+	if len(unknown) > 0 {
+		fmt.Fprintf(out, "⚠️ %d currencies in your wallet are not in the content catalog yet: %s\n", len(unknown), strings.Join(unknown, ", "))
+	}
 }
@@ -0,0 +1,7 @@
+package tui
+
+import "errors"
+
+var (
+	ErrorNotTerminal = errors.New("not_a_terminal_error")
+)
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// readKeys turns terminal input into key names like "up", "pgdn", "ctrl-c" or the typed character
+func readKeys(r io.Reader, keys chan<- string) {
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+
+		for _, key := range parseKeys(buf[:n]) {
+			keys <- key
+		}
+	}
+}
+
+var csiKeys = map[byte]string{'A': "up", 'B': "down", 'C': "right", 'D': "left", 'H': "home", 'F': "end", 'Z': "backtab"}
+
+var tildeKeys = map[byte]string{'1': "home", '7': "home", '4': "end", '8': "end", '5': "pgup", '6': "pgdn"}
+
+func parseKeys(input []byte) []string {
+	keys := []string{}
+	for i := 0; i < len(input); {
+		switch b := input[i]; {
+		case b == 3:
+			keys = append(keys, "ctrl-c")
+			i++
+		case b == '\t':
+			keys = append(keys, "tab")
+			i++
+		case b == '\r' || b == '\n':
+			keys = append(keys, "enter")
+			i++
+		case b == 0x1b && i+2 < len(input) && (input[i+1] == '[' || input[i+1] == 'O'):
+			if key, ok := csiKeys[input[i+2]]; ok {
+				keys = append(keys, key)
+				i += 3
+				continue
+			}
+
+			// ESC [ 5 ~ and friends
+			if i+3 < len(input) && input[i+3] == '~' {
+				if key, ok := tildeKeys[input[i+2]]; ok {
+					keys = append(keys, key)
+				}
+				i += 4
+				continue
+			}
+
+			// skip sequences we don't know up to their final byte
+			i += 2
+			for i < len(input) && (input[i] < 0x40 || input[i] > 0x7e) {
+				i++
+			}
+			i++
+		case b == 0x1b:
+			keys = append(keys, "esc")
+			i++
+		default:
+			r, size := utf8.DecodeRune(input[i:])
+			keys = append(keys, string(r))
+			i += size
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,13 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+
+package tui
+
+import "os"
+
+func makeRaw(in, out *os.File) (func() error, error) {
+	return nil, ErrorNotTerminal
+}
+
+func termSize(out *os.File) (int, int, error) {
+	return 0, 0, ErrorNotTerminal
+}
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// makeRaw switches the console to virtual terminal input and output, so keys arrive as
+// the same escape sequences as on unix and the tui's escape codes are understood
+func makeRaw(in, out *os.File) (func() error, error) {
+	inHandle, outHandle := windows.Handle(in.Fd()), windows.Handle(out.Fd())
+
+	var inMode, outMode uint32
+	if err := windows.GetConsoleMode(inHandle, &inMode); err != nil {
+		return nil, ErrorNotTerminal
+	}
+	if err := windows.GetConsoleMode(outHandle, &outMode); err != nil {
+		return nil, ErrorNotTerminal
+	}
+
+	raw := inMode&^(windows.ENABLE_ECHO_INPUT|windows.ENABLE_PROCESSED_INPUT|windows.ENABLE_LINE_INPUT) | windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+	if err := windows.SetConsoleMode(inHandle, raw); err != nil {
+		return nil, err
+	}
+	if err := windows.SetConsoleMode(outHandle, outMode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+		windows.SetConsoleMode(inHandle, inMode)
+		return nil, err
+	}
+
+	return func() error {
+		windows.SetConsoleMode(outHandle, outMode)
+		return windows.SetConsoleMode(inHandle, inMode)
+	}, nil
+}
+
+func termSize(out *os.File) (int, int, error) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(out.Fd()), &info); err != nil {
+		return 0, 0, err
+	}
+
+	return int(info.Window.Right - info.Window.Left + 1), int(info.Window.Bottom - info.Window.Top + 1), nil
+}
@@ -0,0 +1,568 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goamaan/valocli/internal/chat"
+	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/player"
+	"github.com/goamaan/valocli/internal/store"
+)
+
+const (
+	DefaultInterval = 5 * time.Minute
+	matchCount      = 10
+
+	checkInterval = 10 * time.Second
+
+	enterScreen = "\033[?1049h\033[?25l"
+	leaveScreen = "\033[?25h\033[?1049l"
+	inverse     = "\033[7m"
+	red         = "\033[31m"
+	reset       = "\033[0m"
+	clearLine   = "\033[K"
+)
+
+type Options struct {
+	// Refresh gets the client new tokens, it's called shortly before they expire and
+	// whenever riot rejects them
+	Refresh func() error
+	// Chat is where the friends tab connects to
+	Chat chat.Options
+	// Interval is how often tabs that have been opened are fetched again
+	Interval time.Duration
+}
+
+// App is the full screen interface, every tab fetches its data with the same functions
+// as the matching subcommand and renders it with the subcommand's printer
+type App struct {
+	session *core.Session
+	opts    Options
+	tabs    []*tab
+	redraw  chan struct{}
+
+	mu      sync.Mutex
+	active  int
+	player  string
+	message string
+	conn    *chat.Conn
+}
+
+type tab struct {
+	name   string
+	fetch  func(c *core.Client) (any, error)
+	render func(w io.Writer, data any) error
+	// stale reports data that's out of date before the refresh interval is up
+	stale func(data any) bool
+
+	data      any
+	err       error
+	fetchedAt time.Time
+	loading   bool
+	scroll    int
+}
+
+type walletTab struct {
+	wallet     *store.WalletResponse
+	currencies store.Currencies
+}
+
+func New(c *core.Client, opts Options) *App {
+	if opts.Interval == 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	a := &App{session: core.NewSession(c, opts.Refresh), opts: opts, redraw: make(chan struct{}, 1)}
+	a.tabs = []*tab{
+		{name: "Store", fetch: fetchStore, render: renderStore, stale: storeRotated},
+		{name: "Wallet", fetch: fetchWallet, render: renderWallet},
+		{name: "Rank", fetch: fetchRank, render: renderRank},
+		{name: "Matches", fetch: fetchMatches, render: renderMatches},
+		{name: "Loadout", fetch: fetchLoadout, render: renderLoadout},
+		{name: "Friends", fetch: a.fetchFriends, render: renderFriends, stale: chatDisconnected},
+	}
+
+	return a
+}
+
+// Run takes over the terminal until the player quits, ErrorNotTerminal means stdin or
+// stdout isn't a terminal and the caller should fall back to plain output
+func Run(c *core.Client, opts Options) error {
+	return New(c, opts).Run()
+}
+
+func (a *App) Run() error {
+	restore, err := makeRaw(os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	fmt.Print(enterScreen)
+	defer fmt.Print(leaveScreen)
+
+	// the data functions log their progress, show it in the status bar instead
+	previous := log.Writer()
+	log.SetOutput(a)
+	defer log.SetOutput(previous)
+
+	defer func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if a.conn != nil {
+			a.conn.Close()
+		}
+	}()
+
+	keys := make(chan string, 16)
+	go readKeys(os.Stdin, keys)
+	go a.refreshLoop()
+	go func() {
+		name, _ := a.session.Do(func(c *core.Client) (any, error) {
+			return player.Names(c).Name(c.AuthData.UserId), nil
+		})
+		a.mu.Lock()
+		a.player = name.(string)
+		a.mu.Unlock()
+		a.requestRedraw()
+	}()
+	go a.load(a.tabs[0])
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		a.draw()
+
+		select {
+		case key, ok := <-keys:
+			if !ok || !a.handleKey(key) {
+				return nil
+			}
+		case <-a.redraw:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Write takes log output and keeps its last line for the status bar
+func (a *App) Write(p []byte) (int, error) {
+	lines := strings.Split(strings.TrimSpace(string(p)), "\n")
+
+	a.mu.Lock()
+	a.message = strings.TrimSpace(lines[len(lines)-1])
+	a.mu.Unlock()
+	a.requestRedraw()
+
+	return len(p), nil
+}
+
+func (a *App) requestRedraw() {
+	select {
+	case a.redraw <- struct{}{}:
+	default:
+	}
+}
+
+// handleKey returns false when the player quits
+func (a *App) handleKey(key string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	t := a.tabs[a.active]
+	_, height, _ := termSize(os.Stdout)
+	page := bodyHeight(height) - 1
+	if page < 1 {
+		page = 1
+	}
+
+	switch key {
+	case "q", "ctrl-c":
+		return false
+	case "right", "tab", "l":
+		a.switchTab((a.active + 1) % len(a.tabs))
+	case "left", "backtab", "h":
+		a.switchTab((a.active + len(a.tabs) - 1) % len(a.tabs))
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if i := int(key[0] - '1'); i < len(a.tabs) {
+			a.switchTab(i)
+		}
+	case "down", "j":
+		t.scroll++
+	case "up", "k":
+		t.scroll--
+	case "pgdn", " ":
+		t.scroll += page
+	case "pgup":
+		t.scroll -= page
+	case "home", "g":
+		t.scroll = 0
+	case "end", "G":
+		t.scroll = 1 << 30
+	case "r":
+		go a.load(t)
+	}
+
+	return true
+}
+
+// switchTab shows another tab, fetching it the first time it's opened, a.mu must be held
+func (a *App) switchTab(i int) {
+	a.active = i
+	t := a.tabs[i]
+	if t.fetchedAt.IsZero() && !t.loading {
+		go a.load(t)
+	}
+}
+
+// load fetches the data of a tab, unless it's already being fetched
+func (a *App) load(t *tab) {
+	a.mu.Lock()
+	if t.loading {
+		a.mu.Unlock()
+		return
+	}
+	t.loading = true
+	a.mu.Unlock()
+	a.requestRedraw()
+
+	data, err := a.session.Do(t.fetch)
+
+	a.mu.Lock()
+	t.loading = false
+	t.fetchedAt = time.Now()
+	t.err = err
+	if err == nil {
+		t.data = data
+	}
+	a.mu.Unlock()
+	a.requestRedraw()
+}
+
+// refreshLoop keeps the tokens valid and fetches opened tabs again once they're out of date
+func (a *App) refreshLoop() {
+	for range time.Tick(checkInterval) {
+		if err := a.session.RefreshIfExpiring(); err != nil {
+			log.Printf("could not refresh tokens: %s", err)
+		}
+
+		for _, t := range a.tabs {
+			a.mu.Lock()
+			due := !t.fetchedAt.IsZero() && !t.loading &&
+				(time.Since(t.fetchedAt) > a.opts.Interval || (t.stale != nil && t.data != nil && t.stale(t.data)))
+			a.mu.Unlock()
+
+			if due {
+				go a.load(t)
+			}
+		}
+	}
+}
+
+func (a *App) draw() {
+	width, height, err := termSize(os.Stdout)
+	if err != nil || width < 20 || height < 5 {
+		width, height = 80, 24
+	}
+
+	a.mu.Lock()
+	t := a.tabs[a.active]
+	data, err := t.data, t.err
+	a.mu.Unlock()
+
+	// render without the lock, printers may look up content the first time
+	var body []string
+	switch {
+	case data == nil && err != nil:
+		for _, line := range wrap("⚠️ "+err.Error(), width) {
+			body = append(body, red+line+reset)
+		}
+	case data == nil:
+		body = []string{"Loading " + t.name + "..."}
+	default:
+		buf := new(bytes.Buffer)
+		if err := t.render(buf, data); err != nil {
+			fmt.Fprintf(buf, "⚠️ %s\n", err)
+		}
+		body = strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	rows := bodyHeight(height)
+	if t.scroll > len(body)-rows {
+		t.scroll = len(body) - rows
+	}
+	if t.scroll < 0 {
+		t.scroll = 0
+	}
+
+	screen := new(strings.Builder)
+	screen.WriteString("\033[H")
+	screen.WriteString(a.tabBar() + clearLine + "\r\n")
+	screen.WriteString(truncate(strings.Repeat("─", width), width) + clearLine + "\r\n")
+	for i := 0; i < rows; i++ {
+		if line := t.scroll + i; line < len(body) {
+			screen.WriteString(truncate(body[line], width))
+		}
+		screen.WriteString(clearLine + "\r\n")
+	}
+	screen.WriteString(inverse + pad(a.statusLine(t), width) + reset + "\r\n")
+	screen.WriteString(truncate(a.helpLine(t, len(body), rows), width) + clearLine)
+
+	os.Stdout.WriteString(screen.String())
+}
+
+func (a *App) tabBar() string {
+	bar := new(strings.Builder)
+	for i, t := range a.tabs {
+		label := fmt.Sprintf(" %d %s ", i+1, t.name)
+		if i == a.active {
+			label = inverse + label + reset
+		}
+		bar.WriteString(label)
+	}
+
+	return bar.String()
+}
+
+// statusLine shows the region, player, token expiry and when the tab was fetched, a.mu must be held
+func (a *App) statusLine(t *tab) string {
+	parts := []string{strings.ToUpper(a.session.Region())}
+	if a.player != "" {
+		parts = append(parts, a.player)
+	}
+
+	if expiresIn := time.Until(a.session.TokenExpiry()); expiresIn > 0 {
+		parts = append(parts, "token expires in "+store.FormatCountdown(expiresIn))
+	} else {
+		parts = append(parts, "token expired")
+	}
+
+	switch {
+	case t.loading:
+		parts = append(parts, "refreshing "+t.name+"...")
+	case !t.fetchedAt.IsZero():
+		parts = append(parts, "updated "+t.fetchedAt.Format("15:04:05"))
+	}
+	if t.data != nil && t.err != nil {
+		parts = append(parts, "refresh failed: "+t.err.Error())
+	}
+	if a.message != "" {
+		parts = append(parts, a.message)
+	}
+
+	return " " + strings.Join(parts, " · ")
+}
+
+func (a *App) helpLine(t *tab, lines, rows int) string {
+	help := " ←/→ tabs · 1-6 jump · ↑/↓ PgUp/PgDn scroll · r refresh · q quit"
+	if lines > rows {
+		last := t.scroll + rows
+		if last > lines {
+			last = lines
+		}
+		help += fmt.Sprintf(" · lines %d-%d of %d", t.scroll+1, last, lines)
+	}
+
+	return help
+}
+
+func bodyHeight(height int) int {
+	// tab bar, separator, status bar and help line
+	if height < 5 {
+		return 1
+	}
+
+	return height - 4
+}
+
+// truncate cuts a line to width visible characters, escape codes don't count
+func truncate(line string, width int) string {
+	if visibleLen(line) <= width {
+		return line
+	}
+
+	out := new(strings.Builder)
+	visible := 0
+	escape := false
+	for _, r := range line {
+		switch {
+		case r == '\033':
+			escape = true
+		case escape:
+			escape = !(r >= '@' && r <= '~' && r != '[')
+		case visible == width-1:
+			out.WriteString("…" + reset)
+			return out.String()
+		default:
+			visible++
+		}
+		out.WriteRune(r)
+	}
+
+	return out.String()
+}
+
+func visibleLen(line string) int {
+	visible := 0
+	escape := false
+	for _, r := range line {
+		switch {
+		case r == '\033':
+			escape = true
+		case escape:
+			escape = !(r >= '@' && r <= '~' && r != '[')
+		default:
+			visible++
+		}
+	}
+
+	return visible
+}
+
+// wrap breaks text into lines of at most width characters, for errors which are one long line
+func wrap(text string, width int) []string {
+	runes := []rune(text)
+	lines := []string{}
+	for len(runes) > width {
+		lines = append(lines, string(runes[:width]))
+		runes = runes[width:]
+	}
+
+	return append(lines, string(runes))
+}
+
+func pad(line string, width int) string {
+	line = truncate(line, width)
+	if missing := width - visibleLen(line); missing > 0 {
+		line += strings.Repeat(" ", missing)
+	}
+
+	return line
+}
+
+func fetchStore(c *core.Client) (any, error) {
+	return store.GetStoreTable(c)
+}
+
+func renderStore(w io.Writer, data any) error {
+	store.FprintStore(w, data.(*store.StoreCliTable).Live())
+	return nil
+}
+
+// storeRotated fetches the store again as soon as the daily offers reset
+func storeRotated(data any) bool {
+	return data.(*store.StoreCliTable).Live().DailyStoreRemaining <= 0
+}
+
+func fetchWallet(c *core.Client) (any, error) {
+	wallet, err := store.FetchWallet(c)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies, err := store.GetCurrencies()
+	if err != nil {
+		return nil, err
+	}
+
+	return &walletTab{wallet: wallet, currencies: currencies}, nil
+}
+
+func renderWallet(w io.Writer, data any) error {
+	wallet := data.(*walletTab)
+	store.FprintWallet(w, wallet.wallet, wallet.currencies)
+	return nil
+}
+
+func fetchRank(c *core.Client) (any, error) {
+	return player.RankFor(c, c.AuthData.UserId)
+}
+
+func renderRank(w io.Writer, data any) error {
+	player.FprintPlayerRanks(w, []player.PlayerRank{*data.(*player.PlayerRank)})
+	return nil
+}
+
+func fetchMatches(c *core.Client) (any, error) {
+	return player.RecentMatches(c, c.AuthData.UserId, "", matchCount)
+}
+
+func renderMatches(w io.Writer, data any) error {
+	player.FprintMatches(w, data.([]player.MatchSummary))
+	return nil
+}
+
+func fetchLoadout(c *core.Client) (any, error) {
+	return player.Loadout(c, c.AuthData.UserId)
+}
+
+func renderLoadout(w io.Writer, data any) error {
+	player.FprintLoadout(w, data.(*player.PlayerLoadout))
+	return nil
+}
+
+// fetchFriends connects to chat once, presences then keep arriving and the tab redraws with them
+func (a *App) fetchFriends(c *core.Client) (any, error) {
+	a.mu.Lock()
+	conn := a.conn
+	a.mu.Unlock()
+
+	if conn != nil {
+		select {
+		case <-conn.Done():
+		default:
+			return conn, nil
+		}
+	}
+
+	conn, err := chat.Connect(c, a.opts.Chat)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	a.conn = conn
+	a.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case <-conn.Updates():
+				a.requestRedraw()
+			case <-conn.Done():
+				log.Printf("chat disconnected: %s", conn.Err())
+				return
+			}
+		}
+	}()
+
+	return conn, nil
+}
+
+func renderFriends(w io.Writer, data any) error {
+	friends := data.(*chat.Conn).Friends()
+	if err := chat.SortFriends(friends, "status"); err != nil {
+		return err
+	}
+
+	return chat.FprintFriends(w, friends)
+}
+
+// chatDisconnected reconnects the friends tab on the next check after chat drops
+func chatDisconnected(data any) bool {
+	select {
+	case <-data.(*chat.Conn).Done():
+		return true
+	default:
+		return false
+	}
+}
@@ -1,33 +1,159 @@
 package main
 
 import (
+	"crypto/tls"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/goamaan/valocli/internal/chat"
+	"github.com/goamaan/valocli/internal/content"
 	"github.com/goamaan/valocli/internal/core"
+	"github.com/goamaan/valocli/internal/events"
+	"github.com/goamaan/valocli/internal/i18n"
 	"github.com/goamaan/valocli/internal/player"
 	"github.com/goamaan/valocli/internal/store"
+	"github.com/goamaan/valocli/internal/tui"
 )
 
 type AuthConfiguration struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 	Region   string `json:"region"`
+	Language string `json:"language,omitempty"`
+
+	// path to the Riot Client lockfile, used to take tokens from a running client
+	LockfilePath string `json:"lockfilePath,omitempty"`
+
+	// real money currency and VP pack prices used to suggest VP purchases
+	PackCurrency string                    `json:"packCurrency,omitempty"`
+	VPPacks      map[string][]store.VPPack `json:"vpPacks,omitempty"`
+
+	// chat server address (host:port) and xmpp domain, normally taken from the PAS token
+	ChatAddress string `json:"chatAddress,omitempty"`
+	ChatDomain  string `json:"chatDomain,omitempty"`
+
+	// store items to look out for, and where valocli watch sends events
+	Wishlist []string            `json:"wishlist,omitempty"`
+	Sinks    []events.SinkConfig `json:"sinks,omitempty"`
 }
 
 const (
 	ConfigFileDirectory  = ".valocli"
 	ConfigFilePath       = "valocli_config.json"
 	AuthSaveDataFilePath = "valocli_auth_save.json"
+	EventsStateFilePath  = "valocli_events_state.json"
+	PresetsDirectory     = "presets"
 )
 
 func main() {
+	lockfilePath := flag.String("lockfile", "", "path to the Riot Client lockfile (default from config, or the Riot Client's default location)")
+	remote := flag.Bool("remote", false, "always log in with username and password, even if the Riot Client is running")
+	plain := flag.Bool("plain", false, "use the numbered text menu instead of the full screen interface when no command is given")
+	lang := flag.String("lang", "", "language for item names and headings, e.g. pt-BR, ko-KR, es-ES, ja-JP (default from config, or en-US)")
+	flag.Usage = usage
+	flag.Parse()
+
+	var cmd *command
+	if flag.NArg() > 0 {
+		cmd = findCommand(flag.Arg(0))
+		if cmd == nil {
+			fmt.Printf("Unknown command: %s\n\n", flag.Arg(0))
+			usage()
+			os.Exit(2)
+		}
+	}
+
+	// an unsupported language should fail before asking for a password or multi factor code
+	if err := setLanguage(*lang, savedLanguage()); err != nil {
+		log.Fatal(err)
+	}
+
 	client := core.New(nil)
+	config, ok := localLogin(client, *lockfilePath, *remote)
+	if !ok {
+		config = login(client)
+	}
+
+	if cmd == nil {
+		interactive(client, config, *plain)
+		return
+	}
+
+	if err := cmd.Run(client, config, flag.Args()[1:]); err != nil {
+		log.Fatalf("error running %s: %s", cmd.Name, err)
+	}
+}
+
+func setLanguage(flagLanguage, configLanguage string) error {
+	language := flagLanguage
+	if language == "" {
+		language = configLanguage
+	}
+	if language == "" {
+		return nil
+	}
+
+	if !content.IsSupportedLanguage(language) {
+		return fmt.Errorf("unsupported language %q, supported languages are: %s", language, strings.Join(content.Languages, ", "))
+	}
+
+	content.Language = language
+	i18n.Language = language
+	return nil
+}
+
+// localLogin uses the tokens of a running Riot Client when its lockfile can be found
+func localLogin(client *core.Client, lockfilePath string, remote bool) (AuthConfiguration, bool) {
+	var config AuthConfiguration
+	if _, err := os.Stat(getConfigPath()); err == nil {
+		config = loadConfiguration(getConfigPath())
+	}
+
+	if remote {
+		return config, false
+	}
+
+	if lockfilePath == "" {
+		lockfilePath = config.LockfilePath
+	}
+	if lockfilePath == "" {
+		lockfilePath = core.DefaultLockfilePath()
+	}
+
+	lockfile, err := core.ReadLockfile(lockfilePath)
+	if err != nil {
+		if err != core.ErrorLockfileNotFound {
+			fmt.Printf("Could not read Riot Client lockfile: %s. Logging in remotely...\n", err)
+		}
+		return config, false
+	}
+
+	client.UseLockfile(lockfile)
+	if err = client.AuthorizeLocal(); err != nil {
+		fmt.Printf("Could not get tokens from the Riot Client: %s. Logging in remotely...\n", err)
+		client.Lockfile = nil
+		return config, false
+	}
 
+	region, err := client.LocalRegion()
+	if err != nil {
+		region = config.Region
+	}
+	client.Region = region
+
+	fmt.Println("Using tokens from the running Riot Client")
+	return config, true
+}
+
+func login(client *core.Client) AuthConfiguration {
 	config, saveData := readFromConfig()
 	client.Region = config.Region
 	if saveData != nil {
@@ -38,43 +164,93 @@ func main() {
 			fmt.Printf("Got error: %s. Previous tokens have expired. Logging in again...\n", err)
 		} else {
 			saveAuthSaveData(getSaveDataPath(), client.AuthData)
-			cliLoop(client)
-			return
+			return config
 		}
 	}
 
+	if err := authorize(client, config, true); err != nil {
+		panic(err)
+	}
+
+	return config
+}
+
+// authorize logs in with the configured username and password. A multi factor code is only
+// asked for when prompt is set, otherwise the login fails with ErrorRiotAuthentication
+func authorize(client *core.Client, config AuthConfiguration, prompt bool) error {
 	err := client.Authorize(config.Username, config.Password)
 	if err != nil {
-		if err == core.ErrorRiotMultifactor {
-			fmt.Println("Seems like you have Multi factor set up. Enter the code sent to your email: ")
-			var multifactorCode string
-			fmt.Scan(&multifactorCode)
+		if err != core.ErrorRiotMultifactor {
+			return err
+		}
+		if !prompt {
+			return core.ErrorRiotAuthentication
+		}
+
+		fmt.Println("Seems like you have Multi factor set up. Enter the code sent to your email: ")
+		var multifactorCode string
+		fmt.Scan(&multifactorCode)
 
-			err = client.MultiFactorAuth(multifactorCode)
+		err = client.MultiFactorAuth(multifactorCode)
+		if err != nil {
+			return err
+		}
+	}
+
+	saveAuthSaveData(getSaveDataPath(), client.AuthData)
+	return nil
+}
+
+// refreshTokens gets new tokens, before the current ones expire or after riot rejected them.
+// Riot's session cookies are tried before the password, and a multi factor code is only asked
+// for when prompt is set, so the server and the full screen interface never block on stdin
+func refreshTokens(client *core.Client, config AuthConfiguration, prompt bool) error {
+	if client.IsLocal() {
+		return client.AuthorizeLocal()
+	}
+
+	if err := client.Reauthorize(); err == nil {
+		saveAuthSaveData(getSaveDataPath(), client.AuthData)
+		return nil
+	}
+
+	if prompt {
+		fmt.Println("Tokens have expired. Logging in again...")
+	}
+	return authorize(client, config, prompt)
+}
+
+// interactive starts the full screen interface, or the text menu when asked to or when
+// valocli isn't running in a terminal
+func interactive(c *core.Client, config AuthConfiguration, plain bool) {
+	if !plain {
+		err := tui.Run(c, tui.Options{
+			Refresh: func() error { return refreshTokens(c, config, false) },
+			Chat:    config.chatOptions(),
+		})
+		if !errors.Is(err, tui.ErrorNotTerminal) {
 			if err != nil {
-				panic(err)
+				log.Fatal(err)
 			}
-		} else {
-			panic(err)
+			return
 		}
 	}
 
-	saveAuthSaveData(getSaveDataPath(), client.AuthData)
-	cliLoop(client)
-	return
+	cliLoop(c, config)
 }
 
-func cliLoop(c *core.Client) {
+func cliLoop(c *core.Client, config AuthConfiguration) {
 	var response string
 	for {
 		fmt.Println("what do you want to do - enter the corresponding number")
 		fmt.Println("Check Store - 1")
 		fmt.Println("Check Wallet - 2")
 		fmt.Println("Check MMR (Rank Data) - 3")
+		fmt.Println("Check what you can afford - 4")
 		fmt.Println("Quit - 0")
 		fmt.Scan(&response)
 		if response == "1" {
-			err := store.GetStorefront(c)
+			err := withReauth(c, config, func() error { return store.GetStorefront(c) })
 			if err != nil {
 				log.Fatalf("error getting store: %s", err)
 				break
@@ -91,12 +267,47 @@ func cliLoop(c *core.Client) {
 				log.Fatalf("error getting player mmr: %s", err)
 				break
 			}
+		} else if response == "4" {
+			currency, packs := config.vpPacks("")
+			err := store.GetAffordability(c, "", packs, currency)
+			if err != nil {
+				log.Fatalf("error getting affordability: %s", err)
+				break
+			}
 		} else if response == "0" {
 			break
 		}
 	}
 }
 
+// vpPacks returns the pack price table for the requested currency, falling back to
+// the configured currency and the built in price tables
+func (config AuthConfiguration) vpPacks(currency string) (string, []store.VPPack) {
+	if currency == "" {
+		currency = config.PackCurrency
+	}
+	if currency == "" {
+		currency = store.DefaultPackCurrency
+	}
+
+	if packs, ok := config.VPPacks[currency]; ok {
+		return currency, packs
+	}
+
+	return currency, store.DefaultVPPacks[currency]
+}
+
+func (config AuthConfiguration) chatOptions() chat.Options {
+	opts := chat.Options{Address: config.ChatAddress, Domain: config.ChatDomain}
+
+	// a local stand-in server can't have a certificate for 127.0.0.1 signed by anyone we trust
+	if host, _, err := net.SplitHostPort(config.ChatAddress); err == nil && (host == "localhost" || net.ParseIP(host).IsLoopback()) {
+		opts.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return opts
+}
+
 func readFromConfig() (AuthConfiguration, *core.AuthSaveData) {
 	var config AuthConfiguration
 	configPath := getConfigPath()
@@ -139,13 +350,17 @@ func userAuthInput(config *AuthConfiguration) {
 
 func userRegionInput(config *AuthConfiguration) {
 	fmt.Println("What region was your account made in? Enter the corresponding keyword")
-	fmt.Println("North America, Brazil, Latin America - na")
+	fmt.Println("North America - na")
+	fmt.Println("Latin America - latam")
+	fmt.Println("Brazil - br")
 	fmt.Println("Europe - eu")
 	fmt.Println("Asia Pacific - ap")
 	fmt.Println("Korea - kr")
 	var response string
 	fmt.Scan(&response)
-	if response != "na" && response != "eu" && response != "ap" && response != "kr" {
+	switch response {
+	case "na", "latam", "br", "eu", "ap", "kr":
+	default:
 		response = "na"
 	}
 	config.Region = response
@@ -191,6 +406,22 @@ func saveConfiguration(path string, config AuthConfiguration) {
 	}
 }
 
+// savedLanguage returns the language of the config file, without complaining about a missing
+// or broken one as logging in does that
+func savedLanguage() string {
+	data, err := os.ReadFile(getConfigPath())
+	if err != nil {
+		return ""
+	}
+
+	var config AuthConfiguration
+	if err = json.Unmarshal(data, &config); err != nil {
+		return ""
+	}
+
+	return config.Language
+}
+
 func loadConfiguration(path string) AuthConfiguration {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -215,6 +446,10 @@ func getConfigPath() string {
 	return configPath
 }
 
+func getPresetsDirectory() string {
+	return filepath.Join(getConfigDirectory(), PresetsDirectory)
+}
+
 func getSaveDataPath() string {
 	configDir := getConfigDirectory()
 	saveDataPath := filepath.Join(configDir, AuthSaveDataFilePath)
@@ -222,6 +457,10 @@ func getSaveDataPath() string {
 	return saveDataPath
 }
 
+func getEventsStatePath() string {
+	return filepath.Join(getConfigDirectory(), EventsStateFilePath)
+}
+
 func getConfigDirectory() string {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {